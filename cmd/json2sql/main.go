@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"json2sql/codegen"
+	"json2sql/generators"
+	"json2sql/openapi"
+	"json2sql/types"
+	"os"
+	"strings"
+)
+
+const usage = `usage: json2sql <command> [flags] [schema files...]
+
+commands:
+  ddl         print CREATE statements for all things in the schema files
+  insert      read an insert request from stdin and print SQL and parameters
+  select      read a select request from stdin and print SQL and parameters
+  validate    check the schema files
+  diff        print the migration from -from schema files to the schema files
+  openapi     print the OpenAPI document for the schema files
+  go          print Go structs and request constructors for the schema files
+  typescript  print TypeScript types for the schema files
+`
+
+type InsertRequest struct {
+	ThingName string         `json:"thingName"`
+	Values    map[string]any `json:"values"`
+	UserId    any            `json:"userId"`
+	Role      string         `json:"role"`
+}
+
+type SelectRequest struct {
+	ThingName string         `json:"thingName"`
+	FieldsMap map[string]any `json:"fieldsMap"`
+	Page      uint           `json:"page"`
+	Count     uint           `json:"count"`
+	UserId    any            `json:"userId"`
+	Role      string         `json:"role"`
+}
+
+type Output struct {
+	Sql    []string `json:"sql"`
+	Values any      `json:"values,omitempty"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "sql", "output format: sql or json")
+	var from []string
+	flags.Func("from", "schema file to migrate from, may be repeated (diff only)", func(path string) error {
+		from = append(from, path)
+		return nil
+	})
+	title := flags.String("title", "json2sql", "document title (openapi only)")
+	version := flags.String("version", "1.0.0", "document version (openapi only)")
+	packageName := flags.String("package", "things", "package name (go only)")
+	schema := flags.String("schema", "", "Postgres schema for things without one, e.g. v2")
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+
+	if *format != "sql" && *format != "json" {
+		fmt.Fprintf(stderr, "format: %s is not supported\n", *format)
+		return 2
+	}
+
+	registry := &types.Registry{Schema: *schema}
+	things, err := loadSchemaFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, thing := range things {
+		registry.Register(thing)
+	}
+
+	var output Output
+	switch command {
+	case "ddl", "validate":
+		generator := generators.MigrateSchema{Registry: registry}
+		output.Sql, err = generator.GetSql()
+		if command == "validate" && err == nil {
+			fmt.Fprintf(stdout, "%d things are valid\n", len(things))
+			return 0
+		}
+	case "insert":
+		output, err = runInsert(registry, stdin)
+	case "select":
+		output, err = runSelect(registry, stdin)
+	case "diff":
+		var fromThings []types.ThingConfig
+		fromThings, err = loadSchemaFiles(from)
+		if err == nil {
+			generator := generators.MigrateSchema{From: fromThings, Registry: registry}
+			output.Sql, err = generator.GetSql()
+		}
+	case "openapi":
+		generator := openapi.CreateDocument{Title: *title, Version: *version, Registry: registry}
+		var document map[string]any
+		document, err = generator.GetDocument()
+		if err == nil {
+			encoder := json.NewEncoder(stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(document)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	case "go":
+		generator := codegen.CreateGoCode{PackageName: *packageName, Registry: registry}
+		var code string
+		code, err = generator.GetCode()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprint(stdout, code)
+		return 0
+	case "typescript":
+		generator := codegen.CreateTypeScript{Registry: registry}
+		var code string
+		code, err = generator.GetCode()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprint(stdout, code)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "command: %s is not supported\n%s", command, usage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	err = writeOutput(stdout, output, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func loadSchemaFiles(paths []string) ([]types.ThingConfig, error) {
+	things := []types.ThingConfig{}
+	var errs []error
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		fileThings := []types.ThingConfig{}
+		if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+			err = json.Unmarshal(data, &fileThings)
+		} else {
+			thing := types.ThingConfig{}
+			err = json.Unmarshal(data, &thing)
+			fileThings = append(fileThings, thing)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schema file: %s %w", path, err))
+			continue
+		}
+
+		for _, thing := range fileThings {
+			for name, field := range thing.Fields {
+				if field.Name == "" {
+					field.Name = name
+					thing.Fields[name] = field
+				}
+			}
+			things = append(things, thing)
+		}
+	}
+
+	return things, errors.Join(errs...)
+}
+
+func runInsert(registry *types.Registry, stdin io.Reader) (Output, error) {
+	request := InsertRequest{}
+	err := json.NewDecoder(stdin).Decode(&request)
+	if err != nil {
+		return Output{}, err
+	}
+
+	generator := generators.InsertIntoTable{
+		ThingName: request.ThingName,
+		Values:    request.Values,
+		Context:   getRequestContext(request.UserId, request.Role),
+		Registry:  registry,
+	}
+	sql, err := generator.GetSql()
+	if err != nil {
+		return Output{}, err
+	}
+
+	return Output{Sql: []string{sql}, Values: generator.GetValues()}, nil
+}
+
+func runSelect(registry *types.Registry, stdin io.Reader) (Output, error) {
+	request := SelectRequest{Page: 1}
+	err := json.NewDecoder(stdin).Decode(&request)
+	if err != nil {
+		return Output{}, err
+	}
+	if request.Page == 0 {
+		return Output{}, fmt.Errorf("page must be positive integer")
+	}
+
+	generator := generators.SelectFromTable{
+		ThingName: request.ThingName,
+		FieldsMap: request.FieldsMap,
+		Page:      request.Page,
+		Count:     request.Count,
+		Context:   getRequestContext(request.UserId, request.Role),
+		Registry:  registry,
+	}
+	sql, err := generator.GetSql()
+	if err != nil {
+		return Output{}, err
+	}
+
+	output := Output{Sql: []string{sql}}
+	if values := generator.GetWhereValues(); len(values) > 0 {
+		output.Values = values
+	}
+	return output, nil
+}
+
+func getRequestContext(userId any, role string) context.Context {
+	ctx := context.Background()
+	if userId != nil {
+		ctx = types.WithUserId(ctx, userId)
+	}
+	if role != "" {
+		ctx = types.WithRole(ctx, role)
+	}
+	return ctx
+}
+
+func writeOutput(w io.Writer, output Output, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	for _, sql := range output.Sql {
+		fmt.Fprintf(w, "%s;\n\n", sql)
+	}
+
+	if output.Values != nil {
+		values, err := json.Marshal(output.Values)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "-- parameters: %s\n", values)
+	}
+	return nil
+}
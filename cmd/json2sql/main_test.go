@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schema = `[
+  {
+    "name": "parentThing",
+    "fields": {
+      "primaryKey": {"type": "PRIMARY_KEY"},
+      "string": {"type": "STRING", "notNull": true}
+    }
+  }
+]`
+
+func writeSchema(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	err := os.WriteFile(path, []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDdl(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run([]string{"ddl", writeSchema(t, schema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "parent_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT NOT NULL
+);
+
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+}
+
+func TestDdlWithSchema(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run([]string{"ddl", "-schema", "v2", writeSchema(t, schema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `CREATE SCHEMA IF NOT EXISTS "v2";
+
+CREATE TABLE IF NOT EXISTS "v2"."parent_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT NOT NULL
+);
+
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+}
+
+func TestInsert(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	stdin := strings.NewReader(`{"thingName": "parentThing", "values": {"string": "value"}}`)
+	code := run([]string{"insert", "-format", "json", writeSchema(t, schema)}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `{
+  "sql": [
+    "INSERT INTO \"parent_thing\" (\"string\")\nVALUES (:string)"
+  ],
+  "values": {
+    "string": "value"
+  }
+}
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+}
+
+func TestSelect(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	stdin := strings.NewReader(`{"thingName": "parentThing", "fieldsMap": {"string": true, "_where": "string = 'value'"}}`)
+	code := run([]string{"select", writeSchema(t, schema)}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "parent_thing" t
+WHERE t."string" = $1;
+
+-- parameters: ["value"]
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+}
+
+func TestSelectDefaultPage(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	stdin := strings.NewReader(`{"thingName": "parentThing", "fieldsMap": {"string": true}, "count": 10}`)
+	code := run([]string{"select", writeSchema(t, schema)}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "parent_thing" t
+LIMIT 10
+OFFSET 0;
+
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+
+	stdin = strings.NewReader(`{"thingName": "parentThing", "fieldsMap": {"string": true}, "count": 10, "page": 0}`)
+	code = run([]string{"select", writeSchema(t, schema)}, stdin, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected: 1 got: %d", code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	invalidSchema := `{"name": "parentThing", "fields": {"thing": {"type": "THING", "typeThingName": "missingThing"}}}`
+	code := run([]string{"validate", writeSchema(t, invalidSchema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected: 1 got: %d", code)
+	}
+	if stderr.String() == "" {
+		t.Fatal("expected validation error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	newSchema := strings.Replace(schema, `"notNull": true`, `"notNull": false`, 1)
+	fromPath := filepath.Join(t.TempDir(), "old schema.json")
+	err := os.WriteFile(fromPath, []byte(schema), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := run([]string{"diff", "-from", fromPath, writeSchema(t, newSchema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	expected := `ALTER TABLE "parent_thing" ALTER COLUMN "string" DROP NOT NULL;
+
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected: %s got: %s", expected, stdout.String())
+	}
+}
+
+func TestOpenApi(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run([]string{"openapi", "-title", "Things", writeSchema(t, schema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), `"/things/parentThing/{id}"`) || !strings.Contains(stdout.String(), `"title": "Things"`) {
+		t.Fatalf("unexpected document: %s", stdout.String())
+	}
+}
+
+func TestGo(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run([]string{"go", "-package", "models", writeSchema(t, schema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "package models") || !strings.Contains(stdout.String(), "type ParentThing struct") {
+		t.Fatalf("unexpected code: %s", stdout.String())
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run([]string{"typescript", writeSchema(t, schema)}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected: 0 got: %d %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "export interface ParentThing {") {
+		t.Fatalf("unexpected code: %s", stdout.String())
+	}
+}
@@ -0,0 +1,225 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"json2sql/types"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+)
+
+const generatedHeader = "// Code generated by json2sql. DO NOT EDIT."
+
+var goTypeNames = map[types.FieldType]string{
+	types.PRIMARY_KEY: "int64",
+	types.STRING:      "string",
+	types.NUMBER:      "float64",
+	types.BOOLEAN:     "bool",
+	types.DATE:        "time.Time",
+	types.INTEGER:     "int32",
+	types.BIGINT:      "int64",
+	types.DECIMAL:     "string",
+	types.TIMESTAMP:   "time.Time",
+	types.TIME:        "string",
+	types.UUID:        "string",
+	types.JSON:        "any",
+	types.THING:       "int64",
+	types.RELATION:    "int64",
+}
+
+type CreateGoCode struct {
+	PackageName string
+	Registry    *types.Registry
+	imports     map[string]bool
+}
+
+func (cg *CreateGoCode) GetCode() (string, error) {
+	cg.imports = map[string]bool{
+		"context":             true,
+		"json2sql/generators": true,
+		"json2sql/types":      true,
+	}
+
+	parts := []string{}
+	var errs []error
+	for _, thing := range cg.Registry.GetAll() {
+		code, err := cg.getThingCode(thing)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parts = append(parts, code)
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	imports := maps.Keys(cg.imports)
+	sort.Strings(imports)
+	for i, name := range imports {
+		imports[i] = fmt.Sprintf("\t%q", name)
+	}
+
+	code := fmt.Sprintf("%s\n\npackage %s\n\nimport (\n%s\n)\n\n%s\n",
+		generatedHeader, cg.PackageName, strings.Join(imports, "\n"), strings.Join(parts, "\n\n"))
+
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+func (cg *CreateGoCode) getThingCode(thing types.ThingConfig) (string, error) {
+	typeName := strcase.ToCamel(thing.Name)
+	structFields := []string{}
+	fieldConsts := []string{}
+	values := []string{}
+	enums := []string{}
+	var errs []error
+
+	fieldNames := maps.Keys(thing.Fields)
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		field := thing.Fields[fieldName]
+		goName := strcase.ToCamel(fieldName)
+
+		if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			structFields = append(structFields, fmt.Sprintf("%s []%s `db:\"-\" json:\"%s,omitempty\"`",
+				goName, strcase.ToCamel(field.Relation.OtherThingName), fieldName))
+			continue
+		}
+
+		goType, enum, err := cg.getGoType(typeName, goName, field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if enum != "" {
+			enums = append(enums, enum)
+		}
+
+		omitEmpty := ""
+		if !field.NotNull && field.Type != types.PRIMARY_KEY {
+			omitEmpty = ",omitempty"
+			if !strings.HasPrefix(goType, "[]") && goType != "any" {
+				goType = "*" + goType
+			}
+		}
+
+		structFields = append(structFields, fmt.Sprintf("%s %s `db:\"%s\" json:\"%s%s\"`",
+			goName, goType, field.GetColumnName(), fieldName, omitEmpty))
+		fieldConsts = append(fieldConsts, fmt.Sprintf("%sField%s %sField = %q", typeName, goName, typeName, fieldName))
+
+		if field.Type == types.PRIMARY_KEY {
+			continue
+		}
+		value := "t." + goName
+		if strings.HasPrefix(goType, "*") {
+			value = "*" + value
+		}
+		if field.Type == types.ENUM {
+			value = fmt.Sprintf("string(%s)", value)
+		}
+		if omitEmpty == "" {
+			values = append(values, fmt.Sprintf("values[%q] = %s", fieldName, value))
+		} else {
+			values = append(values, fmt.Sprintf("if t.%s != nil {\nvalues[%q] = %s\n}", goName, fieldName, value))
+		}
+	}
+
+	for _, field := range thing.GetSystemFields() {
+		goName := strcase.ToCamel(field.Name)
+		goType, _, err := cg.getGoType(typeName, goName, field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		structFields = append(structFields, fmt.Sprintf("%s %s `db:\"%s\" json:\"%s\"`",
+			goName, goType, types.GetSystemColumnName(field.Name), field.Name))
+		fieldConsts = append(fieldConsts, fmt.Sprintf("%sField%s %sField = %q", typeName, goName, typeName, field.Name))
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	parts := enums
+	parts = append(parts,
+		fmt.Sprintf("type %s struct {\n%s\n}", typeName, strings.Join(structFields, "\n")),
+		fmt.Sprintf("type %sField string\n\nconst (\n%s\n)", typeName, strings.Join(fieldConsts, "\n")),
+		fmt.Sprintf(`func (t *%s) Values() map[string]any {
+values := map[string]any{}
+%s
+return values
+}`, typeName, strings.Join(values, "\n")),
+		fmt.Sprintf(`func New%sInsert(ctx context.Context, registry *types.Registry, t %s) *generators.InsertIntoTable {
+return &generators.InsertIntoTable{
+ThingName: %q,
+Values: t.Values(),
+Context: ctx,
+Registry: registry,
+}
+}`, typeName, typeName, thing.Name),
+		fmt.Sprintf(`func New%sSelect(ctx context.Context, registry *types.Registry, where string, fields ...%sField) *generators.SelectFromTable {
+fieldsMap := map[string]any{}
+for _, field := range fields {
+fieldsMap[string(field)] = ""
+}
+if where != "" {
+fieldsMap["_where"] = where
+}
+return &generators.SelectFromTable{
+ThingName: %q,
+FieldsMap: fieldsMap,
+Context: ctx,
+Registry: registry,
+}
+}`, typeName, typeName, thing.Name),
+		fmt.Sprintf(`func %sFromRows(registry *types.Registry, rows []map[string]any) ([]%s, error) {
+result := []%s{}
+thing, err := registry.Get(%q)
+if err != nil {
+return result, err
+}
+err = thing.ScanInto(rows, &result)
+return result, err
+}`, typeName, typeName, typeName, thing.Name),
+	)
+
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func (cg *CreateGoCode) getGoType(typeName string, goName string, field types.FieldConfig) (string, string, error) {
+	switch field.Type {
+	case types.ENUM:
+		enumName := typeName + goName
+		values := []string{}
+		for _, value := range field.EnumValues {
+			values = append(values, fmt.Sprintf("%s%s %s = %q", enumName, strcase.ToCamel(value), enumName, value))
+		}
+		return enumName, fmt.Sprintf("type %s string\n\nconst (\n%s\n)", enumName, strings.Join(values, "\n")), nil
+	case types.ARRAY:
+		itemType, _, err := cg.getGoType(typeName, goName, field.GetArrayItemField())
+		if err != nil {
+			return "", "", err
+		}
+		if field.ArrayOf == types.ENUM {
+			itemType = "string"
+		}
+		return "[]" + itemType, "", nil
+	}
+
+	goType, ok := goTypeNames[field.Type]
+	if !ok {
+		return "", "", fmt.Errorf("field: %s of type: %s is not supported", field.Name, field.Type)
+	}
+	if goType == "time.Time" {
+		cg.imports["time"] = true
+	}
+	return goType, "", nil
+}
@@ -0,0 +1,114 @@
+package codegen_test
+
+import (
+	"flag"
+	"json2sql/codegen"
+	"json2sql/types"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update generated example files")
+
+var maxNumber = 100.0
+
+var parentThing = types.ThingConfig{
+	Name: "parentThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:    "string",
+			Type:    types.STRING,
+			NotNull: true,
+		},
+		"number": {
+			Name: "number",
+			Type: types.NUMBER,
+			Max:  &maxNumber,
+		},
+		"date": {
+			Name: "date",
+			Type: types.DATE,
+		},
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published"},
+		},
+		"metadata": {
+			Name: "metadata",
+			Type: types.JSON,
+		},
+		"oneToMany": {
+			Name: "oneToMany",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.ONE_TO_MANY,
+				OtherThingName: "childThing",
+				OtherFieldName: "manyToOne",
+			},
+		},
+	},
+}
+
+var childThing = types.ThingConfig{
+	Name: "childThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"tags": {
+			Name:    "tags",
+			Type:    types.ARRAY,
+			ArrayOf: types.STRING,
+		},
+		"manyToOne": {
+			Name: "manyToOne",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.MANY_TO_ONE,
+				OtherThingName: "parentThing",
+				OtherFieldName: "oneToMany",
+			},
+		},
+	},
+}
+
+func compareWithFile(t *testing.T, path string, code string) {
+	if *update {
+		err := os.WriteFile(path, []byte(code), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != string(expected) {
+		t.Fatalf("expected: %s got: %s", expected, code)
+	}
+}
+
+func TestCreateGoCode(t *testing.T) {
+	types.Clear()
+	thing := parentThing
+	thing.Versioned = true
+	thing.Timestamps = true
+	types.Register(thing)
+	types.Register(childThing)
+
+	generator := codegen.CreateGoCode{PackageName: "example"}
+	code, err := generator.GetCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareWithFile(t, "internal/example/things.go", code)
+}
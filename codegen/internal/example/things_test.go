@@ -0,0 +1,91 @@
+package example_test
+
+import (
+	"context"
+	"json2sql/codegen/internal/example"
+	"json2sql/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValues(t *testing.T) {
+	status := example.ParentThingStatusDraft
+	parent := example.ParentThing{
+		PrimaryKey: 1,
+		String:     "string",
+		Status:     &status,
+	}
+
+	expected := map[string]any{
+		"string": "string",
+		"status": "draft",
+	}
+	if !reflect.DeepEqual(parent.Values(), expected) {
+		t.Fatalf("expected: %v got: %v", expected, parent.Values())
+	}
+
+	insert := example.NewParentThingInsert(context.Background(), nil, parent)
+	if insert.ThingName != "parentThing" || !reflect.DeepEqual(insert.Values, expected) {
+		t.Fatalf("unexpected insert: %v", insert)
+	}
+}
+
+func TestNewSelect(t *testing.T) {
+	s := example.NewParentThingSelect(context.Background(), nil, "string = a", example.ParentThingFieldString)
+
+	expected := map[string]any{
+		"string": "",
+		"_where": "string = a",
+	}
+	if s.ThingName != "parentThing" || !reflect.DeepEqual(s.FieldsMap, expected) {
+		t.Fatalf("unexpected select: %v", s)
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	registry := &types.Registry{}
+	registry.Register(types.ThingConfig{
+		Name:      "parentThing",
+		Versioned: true,
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {Name: "primaryKey", Type: types.PRIMARY_KEY},
+			"string":     {Name: "string", Type: types.STRING, NotNull: true},
+			"number":     {Name: "number", Type: types.NUMBER},
+			"date":       {Name: "date", Type: types.DATE},
+			"status":     {Name: "status", Type: types.ENUM, EnumValues: []string{"draft", "published"}},
+			"metadata":   {Name: "metadata", Type: types.JSON},
+		},
+	})
+
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := []map[string]any{{
+		"primaryKey": int64(1),
+		"string":     "string",
+		"number":     []uint8("1.5"),
+		"date":       date,
+		"status":     "published",
+		"metadata":   []uint8(`{"a": 1}`),
+		"_version":   int64(2),
+	}}
+
+	parents, err := example.ParentThingFromRows(registry, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	number := 1.5
+	status := example.ParentThingStatusPublished
+	expected := []example.ParentThing{{
+		PrimaryKey: 1,
+		String:     "string",
+		Number:     &number,
+		Date:       &date,
+		Status:     &status,
+		Metadata:   map[string]any{"a": 1.0},
+		Version:    2,
+	}}
+	if !reflect.DeepEqual(parents, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, parents)
+	}
+}
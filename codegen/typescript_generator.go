@@ -0,0 +1,190 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+)
+
+var typeScriptTypeNames = map[types.FieldType]string{
+	types.PRIMARY_KEY: "number",
+	types.STRING:      "string",
+	types.NUMBER:      "number",
+	types.BOOLEAN:     "boolean",
+	types.DATE:        "string",
+	types.INTEGER:     "number",
+	types.BIGINT:      "number",
+	types.DECIMAL:     "string",
+	types.TIMESTAMP:   "string",
+	types.TIME:        "string",
+	types.UUID:        "string",
+	types.JSON:        "unknown",
+	types.THING:       "number",
+	types.RELATION:    "number",
+}
+
+type CreateTypeScript struct {
+	Registry *types.Registry
+}
+
+func (ct *CreateTypeScript) GetCode() (string, error) {
+	parts := []string{"// Code generated by json2sql. DO NOT EDIT."}
+	var errs []error
+	for _, thing := range ct.Registry.GetAll() {
+		code, err := ct.getThingCode(thing)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parts = append(parts, code)
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	return strings.Join(parts, "\n\n") + "\n", nil
+}
+
+func (ct *CreateTypeScript) getThingCode(thing types.ThingConfig) (string, error) {
+	typeName := strcase.ToCamel(thing.Name)
+	properties := []string{}
+	relations := []string{}
+	insertProperties := []string{}
+	fieldNames := []string{}
+	enums := []string{}
+	var errs []error
+
+	names := maps.Keys(thing.Fields)
+	sort.Strings(names)
+	for _, fieldName := range names {
+		field := thing.Fields[fieldName]
+		readable := !field.Access.Hidden
+
+		if readable && (field.Type == types.THING || field.Type == types.RELATION) {
+			otherThingName := field.TypeThingName
+			if field.Type == types.RELATION {
+				otherThingName = field.Relation.OtherThingName
+			}
+			otherTypeName := strcase.ToCamel(otherThingName)
+			if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+				relations = append(relations, fmt.Sprintf("  %s: %s[];", fieldName, otherTypeName))
+				continue
+			}
+			relations = append(relations, fmt.Sprintf("  %s: %s;", getPropertyName(fieldName, field), getTypeScriptNullable(otherTypeName, field)))
+		} else if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			continue
+		}
+
+		tsType, err := ct.getTypeScriptType(typeName, fieldName, field, &enums)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if readable {
+			fieldNames = append(fieldNames, fmt.Sprintf("%q", fieldName))
+			properties = append(properties, fmt.Sprintf("  %s: %s;", getPropertyName(fieldName, field), getTypeScriptNullable(tsType, field)))
+		}
+
+		if field.Type == types.PRIMARY_KEY || (!field.IsAllowed(types.INSERT, "") && !field.IsAllowed(types.UPDATE, "")) {
+			continue
+		}
+		insertName := fieldName
+		if !field.NotNull || field.Default != "" {
+			insertName += "?"
+		}
+		insertProperties = append(insertProperties, fmt.Sprintf("  %s: %s;", insertName, getTypeScriptNullable(tsType, field)))
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	fieldsMapProperties := []string{
+		fmt.Sprintf("  _where?: %sWhere;", typeName),
+		"  _orderBy?: string;",
+	}
+	if thing.SoftDelete {
+		fieldsMapProperties = append(fieldsMapProperties, "  _withDeleted?: boolean;")
+	}
+	for _, field := range thing.GetSystemFields() {
+		properties = append(properties, fmt.Sprintf("  %s: %s;", getPropertyName(field.Name, field), typeScriptTypeNames[field.Type]))
+		fieldsMapProperties = append(fieldsMapProperties, fmt.Sprintf("  %s?: \"\";", field.Name))
+	}
+
+	whereType := fmt.Sprintf("`${%sField}${\" \" | \".\"}${string}`", typeName)
+	if len(thing.GetSearchableFields()) > 0 {
+		whereType += " | `_search ${string}`"
+	}
+
+	updateType := fmt.Sprintf("Partial<%sInsert>", typeName)
+	if thing.Versioned {
+		updateType += " & { _expectedVersion?: number }"
+	}
+
+	parts := enums
+	parts = append(parts,
+		fmt.Sprintf("export interface %s {\n%s\n}", typeName, strings.Join(properties, "\n")),
+		fmt.Sprintf("export type %sField = %s;", typeName, strings.Join(fieldNames, " | ")),
+		fmt.Sprintf("export type %sWhere = %s;", typeName, whereType),
+		fmt.Sprintf("export type %sFieldsMap = Partial<Record<%sField, \"\">> & {\n%s\n};",
+			typeName, typeName, strings.Join(fieldsMapProperties, "\n")),
+		fmt.Sprintf("export interface %sSelectRequest {\n  thingName: %q;\n  fieldsMap: %sFieldsMap;\n  page?: number;\n  count?: number;\n}",
+			typeName, thing.Name, typeName),
+		fmt.Sprintf("export interface %sInsert {\n%s\n}", typeName, strings.Join(insertProperties, "\n")),
+		fmt.Sprintf("export type %sUpdate = %s;", typeName, updateType),
+	)
+
+	if len(relations) > 0 {
+		parts = append(parts,
+			fmt.Sprintf("export interface %sRelations {\n%s\n}", typeName, strings.Join(relations, "\n")),
+			fmt.Sprintf("export type %sWithRelations = Omit<%s, keyof %sRelations> & %sRelations;",
+				typeName, typeName, typeName, typeName))
+	}
+
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func (ct *CreateTypeScript) getTypeScriptType(typeName string, fieldName string, field types.FieldConfig, enums *[]string) (string, error) {
+	switch field.Type {
+	case types.ENUM:
+		enumName := typeName + strcase.ToCamel(fieldName)
+		values := []string{}
+		for _, value := range field.EnumValues {
+			values = append(values, fmt.Sprintf("%q", value))
+		}
+		*enums = append(*enums, fmt.Sprintf("export type %s = %s;", enumName, strings.Join(values, " | ")))
+		return enumName, nil
+	case types.ARRAY:
+		itemType, err := ct.getTypeScriptType(typeName, fieldName, field.GetArrayItemField(), enums)
+		if err != nil {
+			return "", err
+		}
+		return itemType + "[]", nil
+	}
+
+	tsType, ok := typeScriptTypeNames[field.Type]
+	if !ok {
+		return "", fmt.Errorf("field: %s of type: %s is not supported", field.Name, field.Type)
+	}
+	return tsType, nil
+}
+
+func getPropertyName(fieldName string, field types.FieldConfig) string {
+	if field.NotNull || field.Type == types.PRIMARY_KEY {
+		return fieldName
+	}
+	return fieldName + "?"
+}
+
+func getTypeScriptNullable(tsType string, field types.FieldConfig) string {
+	if field.NotNull || field.Type == types.PRIMARY_KEY {
+		return tsType
+	}
+	return tsType + " | null"
+}
@@ -0,0 +1,29 @@
+package codegen_test
+
+import (
+	"json2sql/codegen"
+	"json2sql/types"
+	"testing"
+)
+
+func TestCreateTypeScript(t *testing.T) {
+	types.Clear()
+	thing := parentThing
+	thing.SoftDelete = true
+	thing.Versioned = true
+	thing.Fields = map[string]types.FieldConfig{}
+	for name, field := range parentThing.Fields {
+		thing.Fields[name] = field
+	}
+	thing.Fields["secret"] = types.FieldConfig{Name: "secret", Type: types.STRING, Searchable: true, Access: types.FieldAccess{Hidden: true}}
+	types.Register(thing)
+	types.Register(childThing)
+
+	generator := codegen.CreateTypeScript{}
+	code, err := generator.GetCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareWithFile(t, "testdata/things.ts", code)
+}
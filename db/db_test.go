@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"json2sql/executors"
 	"json2sql/generators"
+	"json2sql/graphql"
+	"json2sql/handlers"
 	"json2sql/types"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -296,6 +305,344 @@ func TestSimpleSelect(t *testing.T) {
 	})
 }
 
+func TestUpdateVersionConflict(t *testing.T) {
+	thing := types.ThingConfig{
+		Name:      "versionedThing",
+		Versioned: true,
+		Fields: map[string]types.FieldConfig{
+			"primary_key": {
+				Name: "primary_key",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name: "string",
+				Type: types.STRING,
+			},
+		},
+	}
+	types.Register(thing)
+	defer types.Clear()
+
+	doAndRollback(func(tx *sqlx.Tx) {
+		ctx := context.Background()
+		executor := executors.Executor{DB: tx}
+
+		err := executor.CreateTable(ctx, &generators.CreateTable{ThingName: thing.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = executor.Insert(ctx, &generators.InsertIntoTable{
+			ThingName: thing.Name,
+			Values:    map[string]any{"string": "first"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := executor.Select(ctx, &generators.SelectFromTable{
+			ThingName: thing.Name,
+			FieldsMap: map[string]any{"primary_key": "", "_version": ""},
+		})
+		if err != nil || len(rows) != 1 {
+			t.Fatalf("expected 1 row got: %d, err: %v", len(rows), err)
+		}
+		primaryKey := rows[0]["primary_key"]
+		version := rows[0]["_version"]
+		if version != int64(1) {
+			t.Fatalf("expected version: 1 got: %v", version)
+		}
+
+		update := func() error {
+			return executor.Update(ctx, &generators.UpdateTable{
+				ThingName: thing.Name,
+				Values: map[string]any{
+					"primary_key":      primaryKey,
+					"string":           "second",
+					"_expectedVersion": version,
+				},
+			})
+		}
+
+		err = update()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = update()
+		if !errors.Is(err, executors.ErrConflict) {
+			t.Fatalf("expected conflict got: %v", err)
+		}
+
+		err = executor.Update(ctx, &generators.UpdateTable{
+			ThingName: thing.Name,
+			Values: map[string]any{
+				"primary_key":      -1,
+				"string":           "second",
+				"_expectedVersion": 1,
+			},
+		})
+		if !errors.Is(err, executors.ErrNotFound) {
+			t.Fatalf("expected not found got: %v", err)
+		}
+
+		resolver := graphql.Resolver{Executor: &executor}
+		result, err := resolver.Execute(ctx, `mutation ($id: ID!) {
+  updateVersionedThing(id: $id, input: {string: "third"}, expectedVersion: 2) { _version }
+}`, map[string]any{"id": primaryKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updated := result["data"].(map[string]any)["updateVersionedThing"].(map[string]any)
+		if updated["_version"] != int64(3) {
+			t.Fatalf("expected version: 3 got: %v", updated["_version"])
+		}
+	})
+}
+
+func TestAuditUserWithoutTransaction(t *testing.T) {
+	thing := types.ThingConfig{
+		Name:      "auditedThing",
+		Auditable: true,
+		Fields: map[string]types.FieldConfig{
+			"primary_key": {
+				Name: "primary_key",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name: "string",
+				Type: types.STRING,
+			},
+		},
+	}
+	types.Register(thing)
+	defer types.Clear()
+
+	db := getDb()
+	defer db.MustExec(`DROP TABLE IF EXISTS "audited_thing", "audited_thing_history";
+DROP FUNCTION IF EXISTS "audited_thing_audit"() CASCADE`)
+
+	ctx := types.WithUserId(context.Background(), 42)
+	executor := executors.Executor{DB: db}
+
+	err := executor.CreateTable(ctx, &generators.CreateTable{ThingName: thing.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = executor.Insert(ctx, &generators.InsertIntoTable{
+		ThingName: thing.Name,
+		Values:    map[string]any{"string": "first"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changedBy := ""
+	err = db.Get(&changedBy, `SELECT "changed_by" FROM "audited_thing_history"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedBy != "42" {
+		t.Fatalf("expected changed_by: 42 got: %s", changedBy)
+	}
+}
+
+func TestThingHandler(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "handledThing",
+		Fields: map[string]types.FieldConfig{
+			"primary_key": {
+				Name: "primary_key",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name: "string",
+				Type: types.STRING,
+			},
+			"count": {
+				Name: "count",
+				Type: types.BIGINT,
+			},
+		},
+	}
+	types.Register(thing)
+	defer types.Clear()
+
+	doAndRollback(func(tx *sqlx.Tx) {
+		executor := executors.Executor{DB: tx}
+		err := executor.CreateTable(context.Background(), &generators.CreateTable{ThingName: thing.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		handler := handlers.ThingHandler{Executor: &executor}
+		serve := func(method string, target string, body string) (int, map[string]any) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+			result := map[string]any{}
+			decoder := json.NewDecoder(recorder.Body)
+			decoder.UseNumber()
+			decoder.Decode(&result)
+			return recorder.Code, result
+		}
+
+		code, created := serve(http.MethodPost, "/things/handledThing", `{"string": "first", "count": 9007199254740993}`)
+		if code != http.StatusCreated || created["string"] != "first" || created["count"] != json.Number("9007199254740993") {
+			t.Fatalf("unexpected create response: %d %v", code, created)
+		}
+
+		path := fmt.Sprintf("/things/handledThing/%v", created["primary_key"])
+		code, updated := serve(http.MethodPatch, path, `{"string": "second"}`)
+		if code != http.StatusOK || updated["string"] != "second" {
+			t.Fatalf("unexpected update response: %d %v", code, updated)
+		}
+
+		code, _ = serve(http.MethodDelete, path, "")
+		if code != http.StatusNoContent {
+			t.Fatalf("expected: %d got: %d", http.StatusNoContent, code)
+		}
+
+		code, _ = serve(http.MethodGet, path, "")
+		if code != http.StatusNotFound {
+			t.Fatalf("expected: %d got: %d", http.StatusNotFound, code)
+		}
+	})
+}
+
+func TestGraphQLResolver(t *testing.T) {
+	author := types.ThingConfig{
+		Name: "author",
+		Fields: map[string]types.FieldConfig{
+			"primary_key": {Name: "primary_key", Type: types.PRIMARY_KEY},
+			"name":        {Name: "name", Type: types.STRING},
+			"books": {
+				Name: "books",
+				Type: types.RELATION,
+				Relation: types.ThingRelation{
+					Type:           types.ONE_TO_MANY,
+					OtherThingName: "book",
+					OtherFieldName: "author",
+				},
+			},
+		},
+	}
+	book := types.ThingConfig{
+		Name: "book",
+		Fields: map[string]types.FieldConfig{
+			"primary_key": {Name: "primary_key", Type: types.PRIMARY_KEY},
+			"title":       {Name: "title", Type: types.STRING},
+			"author": {
+				Name: "author",
+				Type: types.RELATION,
+				Relation: types.ThingRelation{
+					Type:           types.MANY_TO_ONE,
+					OtherThingName: "author",
+					OtherFieldName: "books",
+				},
+			},
+		},
+	}
+	types.Register(author)
+	types.Register(book)
+	defer types.Clear()
+
+	doAndRollback(func(tx *sqlx.Tx) {
+		ctx := context.Background()
+		executor := executors.Executor{DB: tx}
+		for _, thingName := range []string{author.Name, book.Name} {
+			err := executor.CreateTable(ctx, &generators.CreateTable{ThingName: thingName})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		resolver := graphql.Resolver{Executor: &executor}
+		created, err := resolver.Execute(ctx, `mutation { createAuthor(input: {name: "Ursula"}) { primary_key } }`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		authorId := created["data"].(map[string]any)["createAuthor"].(map[string]any)["primary_key"]
+
+		for _, title := range []string{"Earthsea", "The Dispossessed"} {
+			_, err := resolver.Execute(ctx, `mutation ($input: BookInput!) { createBook(input: $input) { primary_key } }`,
+				map[string]any{"input": map[string]any{"title": title, "author": authorId}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, err := resolver.Execute(ctx, `{
+  authorList { items { name books(filter: {orderBy: "title"}) { title author { name } } } }
+}`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items := result["data"].(map[string]any)["authorList"].(map[string]any)["items"].([]map[string]any)
+		books := items[0]["books"].([]map[string]any)
+		if len(books) != 2 || books[0]["title"] != "Earthsea" || books[1]["author"].(map[string]any)["name"] != "Ursula" {
+			t.Fatalf("unexpected result: %v", result)
+		}
+
+		result, err = resolver.Execute(ctx, `{ authorList { items { __typename } } }`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items = result["data"].(map[string]any)["authorList"].(map[string]any)["items"].([]map[string]any)
+		if len(items) != 1 || items[0]["__typename"] != "Author" {
+			t.Fatalf("unexpected result: %v", result)
+		}
+	})
+}
+
+type taggedThing struct {
+	PrimaryKey int64    `json2sql:"primary_key,primaryKey"`
+	Title      string   `json2sql:"title,notNull"`
+	Price      *float64 `json2sql:"price"`
+}
+
+func TestSelectInto(t *testing.T) {
+	thing, err := types.RegisterStruct(taggedThing{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer types.Clear()
+
+	doAndRollback(func(tx *sqlx.Tx) {
+		ctx := context.Background()
+		executor := executors.Executor{DB: tx}
+
+		err := executor.CreateTable(ctx, &generators.CreateTable{ThingName: thing.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values, err := types.GetStructValues(taggedThing{Title: "first"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = executor.Insert(ctx, &generators.InsertIntoTable{ThingName: thing.Name, Values: values})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := executors.SelectInto[taggedThing](ctx, &executor, &generators.SelectFromTable{
+			ThingName: thing.Name,
+			FieldsMap: map[string]any{"primary_key": "", "title": "", "price": ""},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].Title != "first" || results[0].Price != nil {
+			t.Fatalf("unexpected results: %v", results)
+		}
+	})
+}
+
 func executeCreateTable(ct *generators.CreateTable, tx *sqlx.Tx) error {
 	createTableSql, err := ct.GetSql()
 	if err != nil {
@@ -312,7 +659,7 @@ func executeInsert(iit *generators.InsertIntoTable, tx *sqlx.Tx) error {
 		return err
 	}
 
-	_, err = tx.NamedExec(insertSql, iit.Values)
+	_, err = tx.NamedExec(insertSql, iit.GetValues())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,466 @@
+package executors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"json2sql/generators"
+	"json2sql/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrNotFound       = errors.New("thing not found")
+	ErrConflict       = errors.New("thing was modified concurrently")
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// Dialect defaults to POSTGRES, where history of auditable things is written
+// by triggers. On SQLITE the executor writes it in the same transaction.
+type Executor struct {
+	DB       sqlx.ExtContext
+	Dialect  types.Dialect
+	Registry *types.Registry
+}
+
+// auditedChange identifies the row a write touches. Inserts set recordId
+// once the key is known.
+type auditedChange struct {
+	operation string
+	recordId  any
+}
+
+func (e *Executor) CreateTable(ctx context.Context, ct *generators.CreateTable) error {
+	if ct.Registry == nil {
+		ct.Registry = e.Registry
+	}
+
+	sqls, err := ct.GetSql()
+	if err != nil {
+		return err
+	}
+
+	for _, query := range sqls {
+		_, err := e.DB.ExecContext(ctx, query)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Executor) Insert(ctx context.Context, iit *generators.InsertIntoTable) (sql.Result, error) {
+	if iit.Context == nil {
+		iit.Context = ctx
+	}
+	if iit.Registry == nil {
+		iit.Registry = e.Registry
+	}
+
+	query, err := iit.GetSql()
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	var result sql.Result
+	change := &auditedChange{operation: "INSERT"}
+	err = e.withAuditUser(ctx, iit.Registry, iit.ThingName, iit.Context, change, func(db sqlx.ExtContext) error {
+		result, err = sqlx.NamedExecContext(ctx, db, query, iit.GetValues())
+		if err != nil || e.Dialect != types.SQLITE {
+			return err
+		}
+		change.recordId, err = result.LastInsertId()
+		return err
+	})
+	return result, err
+}
+
+func (e *Executor) InsertReturningKey(ctx context.Context, iit *generators.InsertIntoTable) (any, error) {
+	if iit.Context == nil {
+		iit.Context = ctx
+	}
+	if iit.Registry == nil {
+		iit.Registry = e.Registry
+	}
+	iit.ReturningKey = true
+
+	query, err := iit.GetSql()
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	var key any
+	change := &auditedChange{operation: "INSERT"}
+	err = e.withAuditUser(ctx, iit.Registry, iit.ThingName, iit.Context, change, func(db sqlx.ExtContext) error {
+		rows, err := sqlx.NamedQueryContext(ctx, db, query, iit.GetValues())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		if rows.Next() {
+			err = rows.Scan(&key)
+			if err != nil {
+				return err
+			}
+		}
+		change.recordId = key
+		return rows.Err()
+	})
+	return key, err
+}
+
+func (e *Executor) Update(ctx context.Context, ut *generators.UpdateTable) error {
+	if ut.Context == nil {
+		ut.Context = ctx
+	}
+	if ut.Registry == nil {
+		ut.Registry = e.Registry
+	}
+
+	query, err := ut.GetSql()
+	if err != nil {
+		return invalidRequest(err)
+	}
+
+	thing, err := ut.Registry.Get(ut.ThingName)
+	if err != nil {
+		return err
+	}
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return err
+	}
+
+	change := &auditedChange{operation: "UPDATE", recordId: ut.Values[primaryKey.Name]}
+	return e.withAuditUser(ctx, ut.Registry, ut.ThingName, ut.Context, change, func(db sqlx.ExtContext) error {
+		result, err := sqlx.NamedExecContext(ctx, db, query, ut.GetValues())
+		if err != nil {
+			return err
+		}
+
+		err = checkRowsAffected(result, ErrNotFound)
+		if !errors.Is(err, ErrNotFound) || !thing.Versioned {
+			return err
+		}
+
+		exists, err := rowExists(ctx, db, ut)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrConflict
+		}
+		return ErrNotFound
+	})
+}
+
+func rowExists(ctx context.Context, db sqlx.ExtContext, ut *generators.UpdateTable) (bool, error) {
+	query, err := ut.GetExistsSql()
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, ut.GetValues())
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	exists := rows.Next()
+	return exists, rows.Err()
+}
+
+func (e *Executor) Delete(ctx context.Context, dft *generators.DeleteFromTable) error {
+	if dft.Context == nil {
+		dft.Context = ctx
+	}
+	if dft.Registry == nil {
+		dft.Registry = e.Registry
+	}
+
+	query, err := dft.GetSql()
+	if err != nil {
+		return invalidRequest(err)
+	}
+
+	thing, err := dft.Registry.Get(dft.ThingName)
+	if err != nil {
+		return err
+	}
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return err
+	}
+
+	change := &auditedChange{operation: "DELETE", recordId: dft.Values[primaryKey.Name]}
+	return e.withAuditUser(ctx, dft.Registry, dft.ThingName, dft.Context, change, func(db sqlx.ExtContext) error {
+		result, err := sqlx.NamedExecContext(ctx, db, query, dft.GetValues())
+		if err != nil {
+			return err
+		}
+		return checkRowsAffected(result, ErrNotFound)
+	})
+}
+
+func (e *Executor) Select(ctx context.Context, s *generators.SelectFromTable) ([]map[string]any, error) {
+	if s.Context == nil {
+		s.Context = ctx
+	}
+	if s.Registry == nil {
+		s.Registry = e.Registry
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		return []map[string]any{}, invalidRequest(err)
+	}
+
+	return e.query(ctx, query, s.GetWhereValues())
+}
+
+func SelectInto[T any](ctx context.Context, e *Executor, s *generators.SelectFromTable) ([]T, error) {
+	result := []T{}
+	rows, err := e.Select(ctx, s)
+	if err != nil {
+		return result, err
+	}
+
+	thing, err := s.Registry.Get(s.ThingName)
+	if err != nil {
+		return result, err
+	}
+
+	err = thing.ScanInto(rows, &result)
+	return result, err
+}
+
+func (e *Executor) SelectRelated(ctx context.Context, sr *generators.SelectRelated) ([]map[string]any, error) {
+	if sr.Context == nil {
+		sr.Context = ctx
+	}
+	if sr.Registry == nil {
+		sr.Registry = e.Registry
+	}
+
+	query, err := sr.GetSql()
+	if err != nil {
+		return []map[string]any{}, invalidRequest(err)
+	}
+
+	return e.query(ctx, query, sr.GetWhereValues())
+}
+
+func (e *Executor) SelectByIds(ctx context.Context, sbi *generators.SelectByIds) ([]map[string]any, error) {
+	if sbi.Context == nil {
+		sbi.Context = ctx
+	}
+	if sbi.Registry == nil {
+		sbi.Registry = e.Registry
+	}
+
+	query, err := sbi.GetSql()
+	if err != nil {
+		return []map[string]any{}, invalidRequest(err)
+	}
+
+	return e.query(ctx, query, sbi.GetWhereValues())
+}
+
+func (e *Executor) AttachRelated(ctx context.Context, ar *generators.AttachRelated) error {
+	if ar.Context == nil {
+		ar.Context = ctx
+	}
+	if ar.Registry == nil {
+		ar.Registry = e.Registry
+	}
+
+	query, err := ar.GetSql()
+	if err != nil {
+		return invalidRequest(err)
+	}
+
+	_, err = sqlx.NamedExecContext(ctx, e.DB, query, ar.GetValues())
+	return err
+}
+
+func (e *Executor) DetachRelated(ctx context.Context, dr *generators.DetachRelated) error {
+	if dr.Context == nil {
+		dr.Context = ctx
+	}
+	if dr.Registry == nil {
+		dr.Registry = e.Registry
+	}
+
+	query, err := dr.GetSql()
+	if err != nil {
+		return invalidRequest(err)
+	}
+
+	_, err = sqlx.NamedExecContext(ctx, e.DB, query, dr.GetValues())
+	return err
+}
+
+func (e *Executor) query(ctx context.Context, query string, values []any) ([]map[string]any, error) {
+	result := []map[string]any{}
+	rows, err := e.DB.QueryxContext(ctx, query, values...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := map[string]any{}
+		err = rows.MapScan(m)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, m)
+	}
+
+	return result, rows.Err()
+}
+
+// withAuditUser runs write for auditable things after setting the acting
+// user, or on SQLITE records the change in the history table itself. Both
+// must happen in the same transaction, so one is started when DB is not
+// already one.
+func (e *Executor) withAuditUser(ctx context.Context, registry *types.Registry, thingName string, generatorContext context.Context, change *auditedChange, write func(db sqlx.ExtContext) error) error {
+	thing, err := registry.Get(thingName)
+	if err != nil {
+		return err
+	}
+	if !thing.Auditable {
+		return write(e.DB)
+	}
+
+	audit := func(db sqlx.ExtContext) error {
+		if e.Dialect == types.SQLITE {
+			return writeHistory(ctx, db, registry, thingName, generatorContext, change, write)
+		}
+		err := setAuditUser(ctx, db, generatorContext)
+		if err != nil {
+			return err
+		}
+		return write(db)
+	}
+
+	beginner, ok := e.DB.(txBeginner)
+	if !ok {
+		return audit(e.DB)
+	}
+
+	tx, err := beginner.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = audit(tx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+func setAuditUser(ctx context.Context, db sqlx.ExtContext, generatorContext context.Context) error {
+	setAuditUser := generators.SetAuditUser{Context: generatorContext}
+	_, err := db.ExecContext(ctx, setAuditUser.GetSql(), setAuditUser.GetValues()...)
+	return err
+}
+
+// writeHistory runs write between reading the row before and after it, and
+// records both like the Postgres trigger does.
+func writeHistory(ctx context.Context, db sqlx.ExtContext, registry *types.Registry, thingName string, generatorContext context.Context, change *auditedChange, write func(db sqlx.ExtContext) error) error {
+	var oldValues map[string]any
+	if change.recordId != nil {
+		var err error
+		oldValues, err = selectHistoryRow(ctx, db, registry, thingName, change.recordId)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := write(db)
+	if err != nil {
+		return err
+	}
+
+	newValues, err := selectHistoryRow(ctx, db, registry, thingName, change.recordId)
+	if err != nil {
+		return err
+	}
+
+	insertHistory := generators.InsertHistory{
+		ThingName: thingName,
+		Operation: change.operation,
+		RecordId:  change.recordId,
+		OldValues: oldValues,
+		NewValues: newValues,
+		Context:   generatorContext,
+		Registry:  registry,
+	}
+	if change.operation == "UPDATE" && oldValues[types.DELETED_AT_COLUMN_NAME] == nil && newValues[types.DELETED_AT_COLUMN_NAME] != nil {
+		insertHistory.Operation = "DELETE"
+	}
+
+	query, err := insertHistory.GetSql()
+	if err != nil {
+		return err
+	}
+	_, err = sqlx.NamedExecContext(ctx, db, query, insertHistory.GetValues())
+	return err
+}
+
+// selectHistoryRow returns nil when the row doesn't exist, as after a delete.
+func selectHistoryRow(ctx context.Context, db sqlx.ExtContext, registry *types.Registry, thingName string, recordId any) (map[string]any, error) {
+	selectHistoryRow := generators.SelectHistoryRow{ThingName: thingName, RecordId: recordId, Registry: registry}
+	query, err := selectHistoryRow.GetSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, selectHistoryRow.GetValues())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	row := map[string]any{}
+	err = rows.MapScan(row)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range row {
+		if data, ok := value.([]byte); ok {
+			row[key] = string(data)
+		}
+	}
+	return row, rows.Err()
+}
+
+func invalidRequest(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+}
+
+func checkRowsAffected(result sql.Result, notFoundErr error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFoundErr
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"json2sql/types"
+	"strings"
+)
+
+func isFieldAccessible(field types.FieldConfig, permission types.FieldPermission, ctx context.Context, skipForbidden bool) (bool, error) {
+	if field.IsAllowed(permission, types.GetRole(ctx)) {
+		return true, nil
+	}
+
+	if skipForbidden {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("field: %s is not allowed to %s", field.Name, strings.ToLower(string(permission)))
+}
@@ -0,0 +1,339 @@
+package generators
+
+import (
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type MigrateSchema struct {
+	From     []types.ThingConfig
+	Registry *types.Registry
+}
+
+func (ms *MigrateSchema) GetSql() ([]string, error) {
+	var errs []error
+	ct := CreateTable{Registry: ms.Registry}
+	tables := []string{}
+	alters := []string{}
+	drops := []string{}
+
+	fromThings := map[string]types.ThingConfig{}
+	for _, thing := range ms.From {
+		fromThings[thing.GetTableName()] = thing
+	}
+
+	toThings := ms.Registry.GetAll()
+	for _, toThing := range toThings {
+		fromThing, ok := fromThings[toThing.GetTableName()]
+		if !ok {
+			sql, err := ct.getTableSql(toThing)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			tables = append(tables, sql)
+			continue
+		}
+
+		tableAlters, err := ct.getTableAlters(ms.From, fromThing, toThing)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		alters = append(alters, tableAlters...)
+	}
+
+	for _, fromThing := range ms.From {
+		if slices.ContainsFunc(toThings, func(thing types.ThingConfig) bool { return thing.GetTableName() == fromThing.GetTableName() }) {
+			continue
+		}
+		drops = append(drops, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, fromThing.GetTableName()))
+	}
+
+	results := getSchemaCreateStrings(toThings)
+	results = append(results, tables...)
+	results = append(results, ct.joinTables...)
+	results = append(results, alters...)
+	results = append(results, ct.audits...)
+	results = append(results, ct.indexes...)
+	results = append(results, drops...)
+	return results, errors.Join(errs...)
+}
+
+func (ct *CreateTable) getTableAlters(fromThings []types.ThingConfig, fromThing types.ThingConfig, toThing types.ThingConfig) ([]string, error) {
+	results := []string{}
+	var errs []error
+	tableName := toThing.GetTableName()
+
+	fromColumns, err := getColumnCreateStrings(fromThing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	toColumns, err := getColumnCreateStrings(toThing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	columnNames := maps.Keys(toColumns)
+	sort.Strings(columnNames)
+	for _, columnName := range columnNames {
+		if _, ok := fromColumns[columnName]; !ok && columnName != types.SEARCH_VECTOR_COLUMN_NAME {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, tableName, toColumns[columnName]))
+		}
+	}
+
+	// A generated column can't be altered, so the search vector is recreated
+	// once the columns it reads exist and before the ones it read are dropped.
+	fromSearchVector, hadSearchVector := fromColumns[types.SEARCH_VECTOR_COLUMN_NAME]
+	toSearchVector, hasSearchVector := toColumns[types.SEARCH_VECTOR_COLUMN_NAME]
+	searchVectorChanged := hadSearchVector && hasSearchVector && fromSearchVector != toSearchVector
+	if hadSearchVector && (!hasSearchVector || searchVectorChanged) {
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS "%s"`, tableName, types.SEARCH_VECTOR_COLUMN_NAME))
+	}
+	if hasSearchVector && (!hadSearchVector || searchVectorChanged) {
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, tableName, toSearchVector))
+	}
+
+	for _, toField := range toThing.GetFields() {
+		fromField, ok := fromThing.Fields[toField.Name]
+		if !ok {
+			if toField.Type == types.RELATION && toField.Relation.Type == types.MANY_TO_MANY {
+				otherThing, err := ct.Registry.Get(toField.Relation.OtherThingName)
+				if err == nil {
+					err = ct.addJoinTable(toThing, toField, otherThing)
+				}
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+
+		fieldAlters, err := getFieldAlters(toThing, fromField, toField)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		results = append(results, fieldAlters...)
+	}
+
+	uniqueAlters, err := getUniqueConstraintAlters(fromThing, toThing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	results = append(results, uniqueAlters...)
+
+	columnNames = maps.Keys(fromColumns)
+	sort.Strings(columnNames)
+	for _, columnName := range columnNames {
+		if _, ok := toColumns[columnName]; !ok && columnName != types.SEARCH_VECTOR_COLUMN_NAME {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS "%s"`, tableName, columnName))
+		}
+	}
+
+	for _, fromField := range fromThing.GetFields() {
+		_, ok := toThing.Fields[fromField.Name]
+		if !ok && fromField.Type == types.RELATION && fromField.Relation.Type == types.MANY_TO_MANY {
+			otherThing := fromThing
+			for _, thing := range fromThings {
+				if thing.Name == fromField.Relation.OtherThingName {
+					otherThing = thing
+				}
+			}
+			results = append(results, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, fromThing.GetJoinTable(fromField, otherThing).GetTableName()))
+		}
+	}
+
+	fromIndexes, err := getIndexCreateStrings(fromThing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	toIndexes, err := getIndexCreateStrings(toThing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, index := range fromIndexes {
+		if !slices.Contains(toIndexes, index) {
+			results = append(results, fmt.Sprintf(`DROP INDEX IF EXISTS %s`, types.QuoteName(fromThing.Schema, getIndexName(index))))
+		}
+	}
+	searchIndexColumn := fmt.Sprintf(`("%s")`, types.SEARCH_VECTOR_COLUMN_NAME)
+	for _, index := range toIndexes {
+		// Dropping the search vector dropped its index as well.
+		if !slices.Contains(fromIndexes, index) || (searchVectorChanged && strings.HasSuffix(index, searchIndexColumn)) {
+			ct.indexes = append(ct.indexes, index)
+		}
+	}
+
+	if toThing.Auditable && !fromThing.Auditable {
+		audits, err := getAuditCreateStrings(toThing, types.POSTGRES)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		ct.audits = append(ct.audits, audits...)
+	} else if fromThing.Auditable && !toThing.Auditable {
+		results = append(results,
+			fmt.Sprintf(`DROP TRIGGER IF EXISTS "%s_audit" ON %s`, toThing.GetBaseTableName(), tableName),
+			fmt.Sprintf(`DROP FUNCTION IF EXISTS %s()`, types.QuoteName(toThing.Schema, toThing.GetBaseTableName()+"_audit")))
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func getColumnCreateStrings(thingConfig types.ThingConfig) (map[string]string, error) {
+	results := getSystemColumnCreateStrings(thingConfig)
+	var errs []error
+
+	for _, field := range thingConfig.GetFields() {
+		fieldCreateString, err := GetTableFieldCreate(field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if fieldCreateString != "" {
+			results[field.GetColumnName()] = fieldCreateString
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func getFieldAlters(thing types.ThingConfig, fromField types.FieldConfig, toField types.FieldConfig) ([]string, error) {
+	results := []string{}
+	tableName := thing.GetTableName()
+	columnName := toField.GetColumnName()
+	if columnName != fromField.GetColumnName() {
+		return results, nil
+	}
+
+	fromType, err := getTableFieldTypeCreate(fromField)
+	if err != nil {
+		return results, err
+	}
+	toType, err := getTableFieldTypeCreate(toField)
+	if err != nil {
+		return results, err
+	}
+	if fromType == "" || toType == "" {
+		return results, nil
+	}
+
+	if fromType != toType {
+		if fromField.Type == types.PRIMARY_KEY || toField.Type == types.PRIMARY_KEY {
+			return results, fmt.Errorf("primary key: %s type cannot be changed by a migration", toField.Name)
+		}
+		columnType := strings.TrimPrefix(toType, fmt.Sprintf(`"%s" `, columnName))
+		// References are created as SERIAL, which is only a column type on CREATE.
+		if columnType == "SERIAL" {
+			columnType = "INTEGER"
+		}
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" TYPE %s USING "%s"::%s`,
+			tableName, columnName, columnType, columnName, columnType))
+	}
+
+	if fromField.NotNull != toField.NotNull {
+		action := "DROP NOT NULL"
+		if toField.NotNull {
+			action = "SET NOT NULL"
+		}
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" %s`, tableName, columnName, action))
+	}
+
+	if fromField.Default != toField.Default {
+		action := "DROP DEFAULT"
+		if toField.Default != "" {
+			action = fmt.Sprintf("SET DEFAULT %s", toField.Default)
+		}
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" %s`, tableName, columnName, action))
+	}
+
+	if fromField.Unique != toField.Unique {
+		constraintName := getConstraintName(thing, []string{columnName}, "key")
+		if toField.Unique {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT "%s" UNIQUE ("%s")`, tableName, constraintName, columnName))
+		} else {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS "%s"`, tableName, constraintName))
+		}
+	}
+
+	if fromField.Check != toField.Check {
+		constraintName := getConstraintName(thing, []string{columnName}, "check")
+		if fromField.Check != "" {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS "%s"`, tableName, constraintName))
+		}
+		if toField.Check != "" {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT "%s" CHECK (%s)`, tableName, constraintName, toField.Check))
+		}
+	}
+
+	if toField.Type == types.ENUM && !slices.Equal(fromField.EnumValues, toField.EnumValues) {
+		alterEnum, err := getAlterEnumSql(thing, toField)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, alterEnum)
+	} else if fromField.Type == types.ENUM && toField.Type != types.ENUM {
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS "%s"`, tableName, fromField.GetEnumConstraintName()))
+	}
+
+	return results, nil
+}
+
+func getUniqueConstraintAlters(fromThing types.ThingConfig, toThing types.ThingConfig) ([]string, error) {
+	results := []string{}
+	var errs []error
+	tableName := toThing.GetTableName()
+
+	fromConstraints := map[string][]string{}
+	for _, fieldNames := range fromThing.Constraints.Unique {
+		columnNames, err := getUniqueConstraintColumnNames(fromThing, fieldNames)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fromConstraints[getConstraintName(fromThing, columnNames, "key")] = columnNames
+	}
+	toConstraints := map[string][]string{}
+	for _, fieldNames := range toThing.Constraints.Unique {
+		columnNames, err := getUniqueConstraintColumnNames(toThing, fieldNames)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		toConstraints[getConstraintName(toThing, columnNames, "key")] = columnNames
+	}
+
+	constraintNames := maps.Keys(fromConstraints)
+	sort.Strings(constraintNames)
+	for _, constraintName := range constraintNames {
+		if _, ok := toConstraints[constraintName]; !ok {
+			results = append(results, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS "%s"`, tableName, constraintName))
+		}
+	}
+
+	constraintNames = maps.Keys(toConstraints)
+	sort.Strings(constraintNames)
+	for _, constraintName := range constraintNames {
+		if _, ok := fromConstraints[constraintName]; ok {
+			continue
+		}
+		columns := []string{}
+		for _, columnName := range toConstraints[constraintName] {
+			columns = append(columns, fmt.Sprintf(`"%s"`, columnName))
+		}
+		results = append(results, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT "%s" UNIQUE (%s)`,
+			tableName, constraintName, strings.Join(columns, ", ")))
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func getIndexName(indexCreate string) string {
+	_, name, _ := strings.Cut(indexCreate, `IF NOT EXISTS "`)
+	name, _, _ = strings.Cut(name, `"`)
+	return name
+}
@@ -0,0 +1,244 @@
+package generators_test
+
+import (
+	"json2sql/generators"
+	"json2sql/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	types.Clear()
+	fromThing := types.ThingConfig{
+		Name: "migratedThing",
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {
+				Name: "primaryKey",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name: "string",
+				Type: types.STRING,
+			},
+			"number": {
+				Name: "number",
+				Type: types.NUMBER,
+			},
+			"removed": {
+				Name: "removed",
+				Type: types.BOOLEAN,
+			},
+		},
+	}
+	toThing := types.ThingConfig{
+		Name:       "migratedThing",
+		Timestamps: true,
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {
+				Name: "primaryKey",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name:      "string",
+				Type:      types.STRING,
+				NotNull:   true,
+				MaxLength: 100,
+			},
+			"number": {
+				Name: "number",
+				Type: types.INTEGER,
+			},
+			"added": {
+				Name:    "added",
+				Type:    types.BOOLEAN,
+				Default: "false",
+			},
+		},
+		Indexes: []types.IndexConfig{
+			{Fields: []types.IndexField{{Name: "string"}}},
+		},
+	}
+	types.Register(toThing)
+	types.Register(otherThing)
+
+	generator := generators.MigrateSchema{
+		From: []types.ThingConfig{fromThing, softDeletedThing},
+	}
+
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`CREATE TABLE IF NOT EXISTS "other_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT
+)`,
+		`ALTER TABLE "migrated_thing" ADD COLUMN "added" BOOLEAN DEFAULT false`,
+		`ALTER TABLE "migrated_thing" ADD COLUMN "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()`,
+		`ALTER TABLE "migrated_thing" ADD COLUMN "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()`,
+		`ALTER TABLE "migrated_thing" ALTER COLUMN "number" TYPE INTEGER USING "number"::INTEGER`,
+		`ALTER TABLE "migrated_thing" ALTER COLUMN "string" TYPE VARCHAR(100) USING "string"::VARCHAR(100)`,
+		`ALTER TABLE "migrated_thing" ALTER COLUMN "string" SET NOT NULL`,
+		`ALTER TABLE "migrated_thing" DROP COLUMN IF EXISTS "removed"`,
+		`CREATE INDEX IF NOT EXISTS "migrated_thing_string_idx" ON "migrated_thing" ("string")`,
+		`DROP TABLE IF EXISTS "soft_deleted_thing"`,
+	}
+
+	if !reflect.DeepEqual(sqls, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, sqls)
+	}
+}
+
+func TestMigrateSchemaToNewVersion(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "migratedThing",
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {
+				Name: "primaryKey",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name: "string",
+				Type: types.STRING,
+			},
+		},
+		Indexes: []types.IndexConfig{
+			{Fields: []types.IndexField{{Name: "string"}}},
+		},
+	}
+	fromThing := thing
+	fromThing.Schema = "v1"
+	registry := &types.Registry{Schema: "v2"}
+	registry.Register(thing)
+
+	generator := generators.MigrateSchema{From: []types.ThingConfig{fromThing}, Registry: registry}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`CREATE SCHEMA IF NOT EXISTS "v2"`,
+		`CREATE TABLE IF NOT EXISTS "v2"."migrated_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT
+)`,
+		`CREATE INDEX IF NOT EXISTS "migrated_thing_string_idx" ON "v2"."migrated_thing" ("string")`,
+		`DROP TABLE IF EXISTS "v1"."migrated_thing"`,
+	}
+	if !reflect.DeepEqual(sqls, expected) {
+		t.Fatalf("expected: %v have: %v", expected, sqls)
+	}
+}
+
+func TestMigrateSchemaSearchable(t *testing.T) {
+	types.Clear()
+	toThing := searchableThing
+	toThing.Fields = map[string]types.FieldConfig{
+		"primaryKey": searchableThing.Fields["primaryKey"],
+		"title":      searchableThing.Fields["title"],
+		"body": {
+			Name:       "body",
+			Type:       types.STRING,
+			Searchable: true,
+		},
+	}
+	types.Register(toThing)
+
+	generator := generators.MigrateSchema{
+		From: []types.ThingConfig{searchableThing},
+	}
+
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`ALTER TABLE "searchable_thing" ADD COLUMN "body" TEXT`,
+		`ALTER TABLE "searchable_thing" DROP COLUMN IF EXISTS "search_vector"`,
+		`ALTER TABLE "searchable_thing" ADD COLUMN "search_vector" tsvector GENERATED ALWAYS AS (to_tsvector('english', coalesce("body", '') || ' ' || coalesce("title", ''))) STORED`,
+		`ALTER TABLE "searchable_thing" DROP COLUMN IF EXISTS "description"`,
+		`ALTER TABLE "searchable_thing" DROP COLUMN IF EXISTS "number"`,
+		`CREATE INDEX IF NOT EXISTS "searchable_thing_search_vector_idx" ON "searchable_thing" USING GIN ("search_vector")`,
+	}
+
+	if !reflect.DeepEqual(sqls, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, sqls)
+	}
+}
+
+func TestMigrateSchemaPrimaryKeyType(t *testing.T) {
+	types.Clear()
+	fromThing := parentThing
+	fromThing.Fields = maps.Clone(parentThing.Fields)
+	fromThing.Fields["primaryKey"] = types.FieldConfig{Name: "primaryKey", Type: types.INTEGER}
+	types.Register(parentThing)
+
+	generator := generators.MigrateSchema{
+		From: []types.ThingConfig{fromThing},
+	}
+
+	_, err := generator.GetSql()
+
+	expectedError := "primary key: primaryKey type cannot be changed by a migration"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestMigrateSchemaConstraints(t *testing.T) {
+	types.Clear()
+	fromThing := types.ThingConfig{
+		Name: "constrainedThing",
+		Constraints: types.ThingConstraints{
+			Unique: [][]string{{"code", "string"}},
+		},
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {Name: "primaryKey", Type: types.PRIMARY_KEY},
+			"code":       {Name: "code", Type: types.STRING, Unique: true},
+			"number":     {Name: "number", Type: types.NUMBER, Check: `"number" >= 0`},
+			"string":     {Name: "string", Type: types.STRING},
+		},
+	}
+	toThing := fromThing
+	toThing.Constraints = types.ThingConstraints{
+		Unique: [][]string{{"string", "number"}},
+	}
+	toThing.Fields = map[string]types.FieldConfig{
+		"primaryKey": {Name: "primaryKey", Type: types.PRIMARY_KEY},
+		"code":       {Name: "code", Type: types.STRING},
+		"number":     {Name: "number", Type: types.NUMBER, Check: `"number" > 0`},
+		"string":     {Name: "string", Type: types.STRING, Unique: true, Check: `"string" <> ''`},
+	}
+	types.Register(toThing)
+
+	generator := generators.MigrateSchema{
+		From: []types.ThingConfig{fromThing},
+	}
+
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`ALTER TABLE "constrained_thing" DROP CONSTRAINT IF EXISTS "constrained_thing_code_key"`,
+		`ALTER TABLE "constrained_thing" DROP CONSTRAINT IF EXISTS "constrained_thing_number_check"`,
+		`ALTER TABLE "constrained_thing" ADD CONSTRAINT "constrained_thing_number_check" CHECK ("number" > 0)`,
+		`ALTER TABLE "constrained_thing" ADD CONSTRAINT "constrained_thing_string_key" UNIQUE ("string")`,
+		`ALTER TABLE "constrained_thing" ADD CONSTRAINT "constrained_thing_string_check" CHECK ("string" <> '')`,
+		`ALTER TABLE "constrained_thing" DROP CONSTRAINT IF EXISTS "constrained_thing_code_string_key"`,
+		`ALTER TABLE "constrained_thing" ADD CONSTRAINT "constrained_thing_string_number_key" UNIQUE ("string", "number")`,
+	}
+
+	if !reflect.DeepEqual(sqls, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, sqls)
+	}
+}
@@ -0,0 +1,40 @@
+package generators
+
+import (
+	"fmt"
+	"json2sql/types"
+)
+
+type AlterTableEnum struct {
+	ThingName string
+	FieldName string
+	Registry  *types.Registry
+}
+
+func (ate *AlterTableEnum) GetSql() (string, error) {
+	thing, err := ate.Registry.Get(ate.ThingName)
+	if err != nil {
+		return "", err
+	}
+
+	field, err := thing.GetField(ate.FieldName)
+	if err != nil {
+		return "", err
+	}
+
+	if field.Type != types.ENUM {
+		return "", fmt.Errorf("field: %s is not enum", field.Name)
+	}
+
+	return getAlterEnumSql(thing, field)
+}
+
+func getAlterEnumSql(thing types.ThingConfig, field types.FieldConfig) (string, error) {
+	if len(field.EnumValues) == 0 {
+		return "", fmt.Errorf("enum field: %s has no values", field.Name)
+	}
+
+	return fmt.Sprintf(`ALTER TABLE %s
+  DROP CONSTRAINT IF EXISTS "%s",
+  ADD %s`, thing.GetTableName(), field.GetEnumConstraintName(), getEnumConstraintCreate(field)), nil
+}
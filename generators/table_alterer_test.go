@@ -0,0 +1,38 @@
+package generators_test
+
+import (
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+)
+
+func TestAlterTableEnum(t *testing.T) {
+	types.Clear()
+	thing := typedThing
+	thing.Fields = map[string]types.FieldConfig{
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published", "archived"},
+		},
+	}
+	types.Register(thing)
+
+	generator := generators.AlterTableEnum{
+		ThingName: thing.Name,
+		FieldName: "status",
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `ALTER TABLE "typed_thing"
+  DROP CONSTRAINT IF EXISTS "status_enum_check",
+  ADD CONSTRAINT "status_enum_check" CHECK ("status" IN ('draft', 'published', 'archived'))`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
@@ -0,0 +1,206 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	recordIdValueName  = "recordId"
+	operationValueName = "operation"
+	changedByValueName = "changedBy"
+	oldValuesValueName = "oldValues"
+	newValuesValueName = "newValues"
+)
+
+var historyOperations = []string{"INSERT", "UPDATE", "DELETE"}
+
+func getAuditCreateStrings(thingConfig types.ThingConfig, dialect types.Dialect) ([]string, error) {
+	primaryKey, err := thingConfig.GetPrimaryKey()
+	if err != nil {
+		return []string{}, err
+	}
+
+	tableName := thingConfig.GetTableName()
+	historyTableName := thingConfig.GetHistoryTableName()
+	triggerName := thingConfig.GetBaseTableName() + "_audit"
+	functionName := types.QuoteName(thingConfig.Schema, triggerName)
+	primaryKeyColumn := primaryKey.GetColumnName()
+
+	if dialect == types.SQLITE {
+		historyTable := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  "history_id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "record_id" INTEGER NOT NULL,
+  "operation" TEXT NOT NULL,
+  "changed_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "changed_by" TEXT,
+  "old_values" TEXT,
+  "new_values" TEXT
+)`, historyTableName)
+		return []string{historyTable}, nil
+	}
+
+	historyTable := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  "history_id" BIGSERIAL PRIMARY KEY,
+  "record_id" INTEGER NOT NULL,
+  "operation" TEXT NOT NULL,
+  "changed_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  "changed_by" TEXT,
+  "old_values" JSONB,
+  "new_values" JSONB
+)`, historyTableName)
+
+	operation := "TG_OP"
+	if thingConfig.SoftDelete {
+		operation = fmt.Sprintf(`CASE WHEN TG_OP = 'UPDATE' AND OLD."%s" IS NULL AND NEW."%s" IS NOT NULL THEN 'DELETE' ELSE TG_OP END`,
+			types.DELETED_AT_COLUMN_NAME, types.DELETED_AT_COLUMN_NAME)
+	}
+
+	function := fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO %s ("record_id", "operation", "changed_by", "old_values")
+    VALUES (OLD."%s", TG_OP, NULLIF(current_setting('%s', true), ''), to_jsonb(OLD));
+    RETURN OLD;
+  END IF;
+  INSERT INTO %s ("record_id", "operation", "changed_by", "old_values", "new_values")
+  VALUES (NEW."%s", %s, NULLIF(current_setting('%s', true), ''), CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) END, to_jsonb(NEW));
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql`,
+		functionName,
+		historyTableName, primaryKeyColumn, types.AUDIT_USER_SETTING_NAME,
+		historyTableName, primaryKeyColumn, operation, types.AUDIT_USER_SETTING_NAME)
+
+	dropTrigger := fmt.Sprintf(`DROP TRIGGER IF EXISTS "%s" ON %s`, triggerName, tableName)
+
+	trigger := fmt.Sprintf(`CREATE TRIGGER "%s"
+AFTER INSERT OR UPDATE OR DELETE ON %s
+FOR EACH ROW EXECUTE FUNCTION %s()`, triggerName, tableName, functionName)
+
+	return []string{historyTable, function, dropTrigger, trigger}, nil
+}
+
+// InsertHistory records a change of an auditable thing from the application,
+// for dialects where history rows are not written by a trigger.
+type InsertHistory struct {
+	ThingName string
+	Operation string
+	RecordId  any
+	OldValues map[string]any
+	NewValues map[string]any
+	Context   context.Context
+	Registry  *types.Registry
+}
+
+func (ih *InsertHistory) GetSql() (string, error) {
+	thing, err := ih.Registry.Get(ih.ThingName)
+	if err != nil {
+		return "", err
+	}
+
+	var errs []error
+	if !thing.Auditable {
+		errs = append(errs, fmt.Errorf("thing: %s is not auditable", thing.Name))
+	}
+	if !slices.Contains(historyOperations, ih.Operation) {
+		errs = append(errs, fmt.Errorf("operation: %s is not one of %s", ih.Operation, strings.Join(historyOperations, ", ")))
+	}
+	if ih.RecordId == nil {
+		errs = append(errs, fmt.Errorf("record id is required to insert history of thing: %s", thing.Name))
+	}
+	for _, values := range []map[string]any{ih.OldValues, ih.NewValues} {
+		_, err := json.Marshal(values)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s ("record_id", "operation", "changed_by", "old_values", "new_values")
+VALUES (:%s, :%s, :%s, :%s, :%s)`, thing.GetHistoryTableName(),
+		recordIdValueName, operationValueName, changedByValueName, oldValuesValueName, newValuesValueName)
+
+	return query, errors.Join(errs...)
+}
+
+func (ih *InsertHistory) GetValues() map[string]any {
+	var changedBy any
+	userId, ok := types.GetUserId(ih.Context)
+	if ok {
+		changedBy = fmt.Sprint(userId)
+	}
+
+	return map[string]any{
+		recordIdValueName:  ih.RecordId,
+		operationValueName: ih.Operation,
+		changedByValueName: changedBy,
+		oldValuesValueName: getHistoryValues(ih.OldValues),
+		newValuesValueName: getHistoryValues(ih.NewValues),
+	}
+}
+
+func getHistoryValues(values map[string]any) any {
+	if values == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+// SelectHistoryRow reads the stored row of an auditable thing, so that it
+// can be recorded by InsertHistory before and after a change.
+type SelectHistoryRow struct {
+	ThingName string
+	RecordId  any
+	Registry  *types.Registry
+}
+
+func (shr *SelectHistoryRow) GetSql() (string, error) {
+	thing, err := shr.Registry.Get(shr.ThingName)
+	if err != nil {
+		return "", err
+	}
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+	if shr.RecordId == nil {
+		return "", fmt.Errorf("record id is required to select history row of thing: %s", thing.Name)
+	}
+
+	query := fmt.Sprintf(`SELECT *
+FROM %s
+WHERE "%s" = :%s`, thing.GetTableName(), primaryKey.GetColumnName(), recordIdValueName)
+	return query, nil
+}
+
+func (shr *SelectHistoryRow) GetValues() map[string]any {
+	return map[string]any{recordIdValueName: shr.RecordId}
+}
+
+type SetAuditUser struct {
+	Context context.Context
+}
+
+func (sau *SetAuditUser) GetSql() string {
+	return fmt.Sprintf("SELECT set_config('%s', $1, true)", types.AUDIT_USER_SETTING_NAME)
+}
+
+func (sau *SetAuditUser) GetValues() []any {
+	userId, ok := types.GetUserId(sau.Context)
+	if !ok {
+		return []any{""}
+	}
+	return []any{fmt.Sprint(userId)}
+}
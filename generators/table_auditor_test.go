@@ -0,0 +1,266 @@
+package generators_test
+
+import (
+	"context"
+	"json2sql/generators"
+	"json2sql/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreateTableAuditable(t *testing.T) {
+	types.Clear()
+	thing := otherThing
+	thing.Auditable = true
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 5 {
+		t.Fatalf("expected 5 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "other_thing_history" (
+  "history_id" BIGSERIAL PRIMARY KEY,
+  "record_id" INTEGER NOT NULL,
+  "operation" TEXT NOT NULL,
+  "changed_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  "changed_by" TEXT,
+  "old_values" JSONB,
+  "new_values" JSONB
+)`
+	if sqls[1] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[1])
+	}
+
+	expected = `CREATE OR REPLACE FUNCTION "other_thing_audit"() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO "other_thing_history" ("record_id", "operation", "changed_by", "old_values")
+    VALUES (OLD."primary_key", TG_OP, NULLIF(current_setting('json2sql.user_id', true), ''), to_jsonb(OLD));
+    RETURN OLD;
+  END IF;
+  INSERT INTO "other_thing_history" ("record_id", "operation", "changed_by", "old_values", "new_values")
+  VALUES (NEW."primary_key", TG_OP, NULLIF(current_setting('json2sql.user_id', true), ''), CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) END, to_jsonb(NEW));
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql`
+	if sqls[2] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[2])
+	}
+
+	expected = `DROP TRIGGER IF EXISTS "other_thing_audit" ON "other_thing"`
+	if sqls[3] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[3])
+	}
+
+	expected = `CREATE TRIGGER "other_thing_audit"
+AFTER INSERT OR UPDATE OR DELETE ON "other_thing"
+FOR EACH ROW EXECUTE FUNCTION "other_thing_audit"()`
+	if sqls[4] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[4])
+	}
+}
+
+func TestCreateTableAuditableWithSchema(t *testing.T) {
+	registry := &types.Registry{Schema: "v2"}
+	thing := otherThing
+	thing.Auditable = true
+	registry.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+		Registry:  registry,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 6 {
+		t.Fatalf("expected 6 queries got: %d", len(sqls))
+	}
+
+	if !strings.HasPrefix(sqls[2], `CREATE TABLE IF NOT EXISTS "v2"."other_thing_history" (`) {
+		t.Fatalf("expected history table in schema v2 have: %s", sqls[2])
+	}
+
+	if !strings.HasPrefix(sqls[3], `CREATE OR REPLACE FUNCTION "v2"."other_thing_audit"() RETURNS TRIGGER AS $$`) {
+		t.Fatalf("expected audit function in schema v2 have: %s", sqls[3])
+	}
+
+	expected := `CREATE TRIGGER "other_thing_audit"
+AFTER INSERT OR UPDATE OR DELETE ON "v2"."other_thing"
+FOR EACH ROW EXECUTE FUNCTION "v2"."other_thing_audit"()`
+	if sqls[5] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[5])
+	}
+}
+
+func TestCreateTableAuditableSoftDelete(t *testing.T) {
+	types.Clear()
+	thing := softDeletedThing
+	thing.Auditable = true
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CASE WHEN TG_OP = 'UPDATE' AND OLD."deleted_at" IS NULL AND NEW."deleted_at" IS NOT NULL THEN 'DELETE' ELSE TG_OP END`
+	if !strings.Contains(sqls[2], expected) {
+		t.Fatalf("expected soft delete to be recorded as DELETE have: %s", sqls[2])
+	}
+}
+
+func TestCreateTableAuditableSqlite(t *testing.T) {
+	types.Clear()
+	thing := otherThing
+	thing.Auditable = true
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+		Dialect:   types.SQLITE,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 2 {
+		t.Fatalf("expected 2 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "other_thing_history" (
+  "history_id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "record_id" INTEGER NOT NULL,
+  "operation" TEXT NOT NULL,
+  "changed_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "changed_by" TEXT,
+  "old_values" TEXT,
+  "new_values" TEXT
+)`
+	if sqls[1] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[1])
+	}
+}
+
+func TestInsertHistory(t *testing.T) {
+	types.Clear()
+	thing := otherThing
+	thing.Auditable = true
+	types.Register(thing)
+
+	generator := generators.InsertHistory{
+		ThingName: thing.Name,
+		Operation: "UPDATE",
+		RecordId:  7,
+		OldValues: map[string]any{"string": "old"},
+		NewValues: map[string]any{"string": "new"},
+		Context:   types.WithUserId(context.Background(), 42),
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "other_thing_history" ("record_id", "operation", "changed_by", "old_values", "new_values")
+VALUES (:recordId, :operation, :changedBy, :oldValues, :newValues)`
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	expectedValues := map[string]any{
+		"recordId":  7,
+		"operation": "UPDATE",
+		"changedBy": "42",
+		"oldValues": `{"string":"old"}`,
+		"newValues": `{"string":"new"}`,
+	}
+	if !reflect.DeepEqual(generator.GetValues(), expectedValues) {
+		t.Fatalf("expected: %v got: %v", expectedValues, generator.GetValues())
+	}
+}
+
+func TestInsertHistoryInvalid(t *testing.T) {
+	types.Clear()
+	types.Register(otherThing)
+
+	generator := generators.InsertHistory{
+		ThingName: otherThing.Name,
+		Operation: "TRUNCATE",
+		Context:   context.Background(),
+	}
+
+	_, err := generator.GetSql()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, message := range []string{"is not auditable", "operation: TRUNCATE", "record id is required"} {
+		if !strings.Contains(err.Error(), message) {
+			t.Fatalf("expected error containing: %s got: %v", message, err)
+		}
+	}
+
+	values := generator.GetValues()
+	if values["changedBy"] != nil || values["oldValues"] != nil {
+		t.Fatalf("expected empty changedBy and oldValues got: %v", values)
+	}
+}
+
+func TestSelectHistoryRow(t *testing.T) {
+	types.Clear()
+	types.Register(otherThing)
+
+	generator := generators.SelectHistoryRow{
+		ThingName: otherThing.Name,
+		RecordId:  7,
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT *
+FROM "other_thing"
+WHERE "primary_key" = :recordId`
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	expectedValues := map[string]any{"recordId": 7}
+	if !reflect.DeepEqual(generator.GetValues(), expectedValues) {
+		t.Fatalf("expected: %v got: %v", expectedValues, generator.GetValues())
+	}
+}
+
+func TestSetAuditUser(t *testing.T) {
+	generator := generators.SetAuditUser{
+		Context: types.WithUserId(context.Background(), 42),
+	}
+
+	expected := "SELECT set_config('json2sql.user_id', $1, true)"
+	if generator.GetSql() != expected {
+		t.Fatalf("expected: %s got: %s", expected, generator.GetSql())
+	}
+
+	values := generator.GetValues()
+	if len(values) != 1 || values[0] != "42" {
+		t.Fatalf("expected: [42] got: %v", values)
+	}
+}
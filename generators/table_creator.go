@@ -4,19 +4,30 @@ import (
 	"errors"
 	"fmt"
 	"json2sql/types"
+	"sort"
 	"strings"
 
 	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
+// Dialect selects the column types of the emitted DDL and defaults to
+// POSTGRES. The other generators always emit Postgres SQL.
 type CreateTable struct {
-	ThingName   string
-	otherThings []types.ThingConfig
-	thing       types.ThingConfig
+	ThingName      string
+	Dialect        types.Dialect
+	Registry       *types.Registry
+	otherThings    []types.ThingConfig
+	thing          types.ThingConfig
+	joinTableNames []string
+	joinTables     []string
+	audits         []string
+	indexes        []string
 }
 
 func (ct *CreateTable) GetSql() ([]string, error) {
-	thing, err := types.Get(ct.ThingName)
+	thing, err := ct.Registry.Get(ct.ThingName)
 	if err != nil {
 		return []string{}, err
 	}
@@ -36,6 +47,12 @@ func (ct *CreateTable) GetSql() ([]string, error) {
 		}
 		results = append(results, sql)
 	}
+	if ct.Dialect != types.SQLITE {
+		results = append(getSchemaCreateStrings(append([]types.ThingConfig{ct.thing}, ct.otherThings...)), results...)
+	}
+	results = append(results, ct.joinTables...)
+	results = append(results, ct.audits...)
+	results = append(results, ct.indexes...)
 	return results, errors.Join(errs...)
 }
 
@@ -44,18 +61,150 @@ func (ct *CreateTable) getTableSql(thingConfig types.ThingConfig) (string, error
 	fields, err := ct.getFieldCreateStrings(thingConfig)
 	fieldsString := strings.Join(fields, ",\n")
 
-	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+	indexes, indexErr := getIndexCreateStrings(thingConfig)
+	ct.indexes = append(ct.indexes, indexes...)
+
+	var auditErr error
+	if thingConfig.Auditable {
+		var audits []string
+		audits, auditErr = getAuditCreateStrings(thingConfig, ct.Dialect)
+		ct.audits = append(ct.audits, audits...)
+	}
+
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
 %s
-)`, tableName, fieldsString), err
+)`, tableName, fieldsString), errors.Join(err, indexErr, auditErr)
+
+}
+
+func getSchemaCreateStrings(things []types.ThingConfig) []string {
+	schemas := []string{}
+	for _, thing := range things {
+		if thing.Schema != "" && !slices.Contains(schemas, thing.Schema) {
+			schemas = append(schemas, thing.Schema)
+		}
+	}
+	sort.Strings(schemas)
+
+	results := []string{}
+	for _, schema := range schemas {
+		results = append(results, fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, schema))
+	}
+	return results
+}
+
+func getIndexCreateStrings(thingConfig types.ThingConfig) ([]string, error) {
+	results := []string{}
+	var errs []error
+	baseTableName := thingConfig.GetBaseTableName()
+	tableName := thingConfig.GetTableName()
+
+	for _, field := range thingConfig.GetFields() {
+		isForeignKey := field.Type == types.THING ||
+			(field.Type == types.RELATION && field.Relation.Type == types.MANY_TO_ONE)
+		if !isForeignKey {
+			continue
+		}
 
+		columnName := field.GetColumnName()
+		results = append(results, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s_%s_idx" ON %s ("%s")`,
+			baseTableName, columnName, tableName, columnName))
+	}
+
+	if len(thingConfig.GetSearchableFields()) > 0 {
+		results = append(results, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s_%s_idx" ON %s USING GIN ("%s")`,
+			baseTableName, types.SEARCH_VECTOR_COLUMN_NAME, tableName, types.SEARCH_VECTOR_COLUMN_NAME))
+	}
+
+	for _, index := range thingConfig.Indexes {
+		indexString, err := getIndexCreate(thingConfig, index)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, indexString)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func getIndexCreate(thingConfig types.ThingConfig, index types.IndexConfig) (string, error) {
+	if len(index.Fields) == 0 {
+		return "", fmt.Errorf("index in thing: %s has no fields", thingConfig.Name)
+	}
+
+	columnNames := []string{}
+	columns := []string{}
+	for _, indexField := range index.Fields {
+		field, err := thingConfig.GetField(indexField.Name)
+		if err != nil {
+			return "", err
+		}
+
+		column := fmt.Sprintf(`"%s"`, field.GetColumnName())
+		if indexField.Descending {
+			column += " DESC"
+		}
+		columnNames = append(columnNames, field.GetColumnName())
+		columns = append(columns, column)
+	}
+
+	name := index.Name
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_idx", thingConfig.GetBaseTableName(), strings.Join(columnNames, "_"))
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	result := fmt.Sprintf(`CREATE %sINDEX IF NOT EXISTS "%s" ON %s (%s)`,
+		unique, name, thingConfig.GetTableName(), strings.Join(columns, ", "))
+
+	if index.Where != "" {
+		compiler := whereCompiler{
+			thing:           thingConfig,
+			inline:          true,
+			skipAccessCheck: true,
+		}
+		whereString, err := compiler.compile(index.Where)
+		if err != nil {
+			return "", err
+		}
+		result += fmt.Sprintf(" WHERE %s", strings.TrimSpace(whereString))
+	}
+
+	return result, nil
 }
 
 func (ct *CreateTable) getFieldCreateStrings(thingConfig types.ThingConfig) ([]string, error) {
 	results := []string{}
 	var errs []error
 
+	systemColumns, err := ct.getSystemColumnCreateStrings(thingConfig)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	for _, field := range thingConfig.GetFields() {
-		fieldCreateString, err := GetTableFieldCreate(field)
+		_, isSystemColumn := systemColumns[field.GetColumnName()]
+		if isSystemColumn {
+			errs = append(errs, fmt.Errorf("field: %s collides with system column: %s", field.Name, field.GetColumnName()))
+			continue
+		}
+
+		if field.Searchable && field.Type != types.STRING {
+			errs = append(errs, fmt.Errorf("field: %s of type: %s cannot be searchable", field.Name, field.Type))
+			continue
+		}
+
+		if field.Searchable && field.Access.Hidden {
+			errs = append(errs, fmt.Errorf("hidden field: %s cannot be searchable", field.Name))
+			continue
+		}
+
+		fieldCreateString, err := getTableFieldCreate(field, ct.Dialect)
 		if err != nil {
 			errs = append(errs, err)
 			continue
@@ -67,7 +216,7 @@ func (ct *CreateTable) getFieldCreateStrings(thingConfig types.ThingConfig) ([]s
 		}
 
 		if field.Type == types.THING {
-			otherThing, err := types.Get(field.TypeThingName)
+			otherThing, err := ct.Registry.Get(field.TypeThingName)
 			if err != nil {
 				errs = append(errs, err)
 			}
@@ -75,39 +224,297 @@ func (ct *CreateTable) getFieldCreateStrings(thingConfig types.ThingConfig) ([]s
 		}
 
 		if field.Type == types.RELATION {
-			otherThing, err := types.Get(field.Relation.OtherThingName)
+			otherThing, err := ct.Registry.Get(field.Relation.OtherThingName)
 			if err != nil {
 				errs = append(errs, err)
 			}
 			ct.otherThings = append(ct.otherThings, otherThing)
+
+			if err == nil && field.Relation.Type == types.MANY_TO_MANY {
+				err := ct.addJoinTable(thingConfig, field, otherThing)
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	columnNames := maps.Keys(systemColumns)
+	sort.Strings(columnNames)
+	for _, columnName := range columnNames {
+		results = append(results, fmt.Sprintf("  %s", systemColumns[columnName]))
+	}
+
+	for _, uniqueFieldNames := range thingConfig.Constraints.Unique {
+		uniqueString, err := getUniqueConstraintCreate(thingConfig, uniqueFieldNames)
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		results = append(results, fmt.Sprintf("  %s", uniqueString))
 	}
 
 	return results, errors.Join(errs...)
 }
 
+func (ct *CreateTable) addJoinTable(thingConfig types.ThingConfig, field types.FieldConfig, otherThing types.ThingConfig) error {
+	joinTable := thingConfig.GetJoinTable(field, otherThing)
+	if slices.Contains(ct.joinTableNames, joinTable.GetTableName()) {
+		return nil
+	}
+
+	thingPrimaryKey, err := thingConfig.GetPrimaryKey()
+	if err != nil {
+		return err
+	}
+
+	otherPrimaryKey, err := otherThing.GetPrimaryKey()
+	if err != nil {
+		return err
+	}
+
+	columns := []string{
+		fmt.Sprintf(`  "%s" INTEGER NOT NULL REFERENCES %s ("%s") ON DELETE CASCADE`,
+			joinTable.ThingColumn, thingConfig.GetTableName(), thingPrimaryKey.GetColumnName()),
+		fmt.Sprintf(`  "%s" INTEGER NOT NULL REFERENCES %s ("%s") ON DELETE CASCADE`,
+			joinTable.OtherColumn, otherThing.GetTableName(), otherPrimaryKey.GetColumnName()),
+	}
+	sort.Strings(columns)
+
+	primaryKeyColumns := []string{joinTable.ThingColumn, joinTable.OtherColumn}
+	sort.Strings(primaryKeyColumns)
+	columns = append(columns, fmt.Sprintf(`  PRIMARY KEY ("%s", "%s")`, primaryKeyColumns[0], primaryKeyColumns[1]))
+
+	ct.joinTableNames = append(ct.joinTableNames, joinTable.GetTableName())
+	ct.indexes = append(ct.indexes, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s_%s_idx" ON %s ("%s")`,
+		joinTable.Name, primaryKeyColumns[1], joinTable.GetTableName(), primaryKeyColumns[1]))
+	ct.joinTables = append(ct.joinTables, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+%s
+)`, joinTable.GetTableName(), strings.Join(columns, ",\n")))
+
+	return nil
+}
+
+func getUniqueConstraintCreate(thingConfig types.ThingConfig, fieldNames []string) (string, error) {
+	columnNames, err := getUniqueConstraintColumnNames(thingConfig, fieldNames)
+	if err != nil {
+		return "", err
+	}
+
+	columns := []string{}
+	for _, columnName := range columnNames {
+		columns = append(columns, fmt.Sprintf(`"%s"`, columnName))
+	}
+
+	return fmt.Sprintf("UNIQUE (%s)", strings.Join(columns, ", ")), nil
+}
+
+func getUniqueConstraintColumnNames(thingConfig types.ThingConfig, fieldNames []string) ([]string, error) {
+	if len(fieldNames) == 0 {
+		return []string{}, fmt.Errorf("unique constraint in thing: %s has no fields", thingConfig.Name)
+	}
+
+	columnNames := []string{}
+	for _, fieldName := range fieldNames {
+		field, err := thingConfig.GetField(fieldName)
+		if err != nil {
+			return []string{}, err
+		}
+		columnNames = append(columnNames, field.GetColumnName())
+	}
+	return columnNames, nil
+}
+
+// getConstraintName returns the name Postgres gives the unnamed UNIQUE and
+// CHECK constraints of CreateTable, so that migrations can drop them.
+func getConstraintName(thingConfig types.ThingConfig, columnNames []string, suffix string) string {
+	return fmt.Sprintf("%s_%s_%s", thingConfig.GetBaseTableName(), strings.Join(columnNames, "_"), suffix)
+}
+
+func (ct *CreateTable) getSystemColumnCreateStrings(thingConfig types.ThingConfig) (map[string]string, error) {
+	results := getSystemColumnCreateStrings(thingConfig)
+	if ct.Dialect != types.SQLITE {
+		return results, nil
+	}
+
+	if _, ok := results[types.SEARCH_VECTOR_COLUMN_NAME]; ok {
+		return results, fmt.Errorf("thing: %s has searchable fields, which %s doesn't support", thingConfig.Name, ct.Dialect)
+	}
+	for _, columnName := range []string{types.CREATED_AT_COLUMN_NAME, types.UPDATED_AT_COLUMN_NAME} {
+		if _, ok := results[columnName]; ok {
+			results[columnName] = fmt.Sprintf(`"%s" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP`, columnName)
+		}
+	}
+	if _, ok := results[types.DELETED_AT_COLUMN_NAME]; ok {
+		results[types.DELETED_AT_COLUMN_NAME] = fmt.Sprintf(`"%s" TEXT`, types.DELETED_AT_COLUMN_NAME)
+	}
+	return results, nil
+}
+
+func getSystemColumnCreateStrings(thingConfig types.ThingConfig) map[string]string {
+	results := map[string]string{}
+	if thingConfig.Constraints.AssignedToUser {
+		results[types.OWNER_COLUMN_NAME] = fmt.Sprintf(`"%s" TEXT NOT NULL`, types.OWNER_COLUMN_NAME)
+	}
+	if thingConfig.Timestamps {
+		results[types.CREATED_AT_COLUMN_NAME] = fmt.Sprintf(`"%s" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()`, types.CREATED_AT_COLUMN_NAME)
+		results[types.UPDATED_AT_COLUMN_NAME] = fmt.Sprintf(`"%s" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()`, types.UPDATED_AT_COLUMN_NAME)
+	}
+	if thingConfig.Versioned {
+		results[types.VERSION_COLUMN_NAME] = fmt.Sprintf(`"%s" INTEGER NOT NULL DEFAULT 1`, types.VERSION_COLUMN_NAME)
+	}
+	searchableFields := thingConfig.GetSearchableFields()
+	if len(searchableFields) > 0 {
+		columns := []string{}
+		for _, field := range searchableFields {
+			columns = append(columns, fmt.Sprintf(`coalesce("%s", '')`, field.GetColumnName()))
+		}
+		results[types.SEARCH_VECTOR_COLUMN_NAME] = fmt.Sprintf(`"%s" tsvector GENERATED ALWAYS AS (to_tsvector(%s, %s)) STORED`,
+			types.SEARCH_VECTOR_COLUMN_NAME, quoteLiteral(thingConfig.GetSearchConfig()), strings.Join(columns, " || ' ' || "))
+	}
+	if thingConfig.SoftDelete {
+		results[types.DELETED_AT_COLUMN_NAME] = fmt.Sprintf(`"%s" TIMESTAMP WITH TIME ZONE`, types.DELETED_AT_COLUMN_NAME)
+	}
+	return results
+}
+
 func GetTableFieldCreate(field types.FieldConfig) (string, error) {
+	return getTableFieldCreate(field, types.POSTGRES)
+}
+
+func getTableFieldCreate(field types.FieldConfig, dialect types.Dialect) (string, error) {
+	getFieldTypeCreate := getTableFieldTypeCreate
+	if dialect == types.SQLITE {
+		getFieldTypeCreate = getSqliteFieldTypeCreate
+	}
+	fieldString, err := getFieldTypeCreate(field)
+	if err != nil || fieldString == "" || field.Type == types.PRIMARY_KEY {
+		return fieldString, err
+	}
+
+	if field.NotNull {
+		fieldString += " NOT NULL"
+	}
+	if field.Unique {
+		fieldString += " UNIQUE"
+	}
+	if field.Default != "" {
+		fieldString += fmt.Sprintf(" DEFAULT %s", field.Default)
+	}
+	if field.Check != "" {
+		fieldString += fmt.Sprintf(" CHECK (%s)", field.Check)
+	}
+	if field.Type == types.ENUM {
+		fieldString += " " + getEnumConstraintCreate(field)
+	}
+
+	return fieldString, nil
+}
+
+func getEnumConstraintCreate(field types.FieldConfig) string {
+	values := []string{}
+	for _, value := range field.EnumValues {
+		values = append(values, quoteLiteral(value))
+	}
+
+	return fmt.Sprintf(`CONSTRAINT "%s" CHECK ("%s" IN (%s))`,
+		field.GetEnumConstraintName(), field.GetColumnName(), strings.Join(values, ", "))
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func getTableFieldTypeCreate(field types.FieldConfig) (string, error) {
 	fieldName := strcase.ToSnake(field.Name)
 	switch field.Type {
 	case types.PRIMARY_KEY:
 		return fmt.Sprintf(`"%s" SERIAL PRIMARY KEY`, fieldName), nil
 	case types.STRING:
+		if field.MaxLength > 0 {
+			return fmt.Sprintf(`"%s" VARCHAR(%d)`, fieldName, field.MaxLength), nil
+		}
 		return fmt.Sprintf(`"%s" TEXT`, fieldName), nil
 	case types.NUMBER:
 		return fmt.Sprintf(`"%s" NUMERIC(18, 4)`, fieldName), nil
+	case types.INTEGER:
+		return fmt.Sprintf(`"%s" INTEGER`, fieldName), nil
+	case types.BIGINT:
+		return fmt.Sprintf(`"%s" BIGINT`, fieldName), nil
+	case types.DECIMAL:
+		precision, scale := field.GetPrecision()
+		return fmt.Sprintf(`"%s" NUMERIC(%d, %d)`, fieldName, precision, scale), nil
 	case types.BOOLEAN:
 		return fmt.Sprintf(`"%s" BOOLEAN`, fieldName), nil
 	case types.DATE:
 		return fmt.Sprintf(`"%s" DATE`, fieldName), nil
+	case types.TIMESTAMP:
+		return fmt.Sprintf(`"%s" TIMESTAMP WITH TIME ZONE`, fieldName), nil
+	case types.TIME:
+		return fmt.Sprintf(`"%s" TIME`, fieldName), nil
+	case types.UUID:
+		return fmt.Sprintf(`"%s" UUID`, fieldName), nil
+	case types.JSON:
+		return fmt.Sprintf(`"%s" JSONB`, fieldName), nil
+	case types.ARRAY:
+		itemType, err := getArrayItemType(field.ArrayOf)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`"%s" %s[]`, fieldName, itemType), nil
+	case types.ENUM:
+		if len(field.EnumValues) == 0 {
+			return "", fmt.Errorf("enum field: %s has no values", field.Name)
+		}
+		return fmt.Sprintf(`"%s" TEXT`, fieldName), nil
 	case types.THING:
 		return fmt.Sprintf(`"%s_id" SERIAL`, fieldName), nil
 	case types.RELATION:
 		if field.Relation.Type == types.MANY_TO_ONE {
 			return fmt.Sprintf(`"%s_id" SERIAL`, fieldName), nil
-		} else if field.Relation.Type == types.ONE_TO_MANY {
+		} else if field.Relation.Type == types.ONE_TO_MANY || field.Relation.Type == types.MANY_TO_MANY {
 			return "", nil
 		}
 	}
 
 	return "", fmt.Errorf("field type: %s is not supported", field.Type)
 }
+
+// SQLite has no JSONB, array, UUID or time zone types, so those values are
+// stored as TEXT.
+func getSqliteFieldTypeCreate(field types.FieldConfig) (string, error) {
+	fieldName := strcase.ToSnake(field.Name)
+	switch field.Type {
+	case types.PRIMARY_KEY:
+		return fmt.Sprintf(`"%s" INTEGER PRIMARY KEY AUTOINCREMENT`, fieldName), nil
+	case types.TIMESTAMP, types.TIME, types.UUID, types.JSON:
+		return fmt.Sprintf(`"%s" TEXT`, fieldName), nil
+	case types.ARRAY:
+		_, err := getArrayItemType(field.ArrayOf)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`"%s" TEXT`, fieldName), nil
+	case types.THING:
+		return fmt.Sprintf(`"%s_id" INTEGER`, fieldName), nil
+	case types.RELATION:
+		if field.Relation.Type == types.MANY_TO_ONE {
+			return fmt.Sprintf(`"%s_id" INTEGER`, fieldName), nil
+		}
+	}
+	return getTableFieldTypeCreate(field)
+}
+
+func getArrayItemType(itemType types.FieldType) (string, error) {
+	switch itemType {
+	case types.STRING:
+		return "TEXT", nil
+	case types.NUMBER:
+		return "NUMERIC", nil
+	case types.INTEGER:
+		return "INTEGER", nil
+	case types.BIGINT:
+		return "BIGINT", nil
+	}
+	return "", fmt.Errorf("array of: %s is not supported", itemType)
+}
@@ -4,6 +4,8 @@ import (
 	"json2sql/generators"
 	"json2sql/types"
 	"testing"
+
+	"golang.org/x/exp/maps"
 )
 
 var parentThing = types.ThingConfig{
@@ -100,6 +102,230 @@ var otherThing = types.ThingConfig{
 	},
 }
 
+var ownedThing = types.ThingConfig{
+	Name: "ownedThing",
+	Constraints: types.ThingConstraints{
+		AssignedToUser: true,
+	},
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+	},
+}
+
+var softDeletedThing = types.ThingConfig{
+	Name:       "softDeletedThing",
+	SoftDelete: true,
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+	},
+}
+
+var timestampedThing = types.ThingConfig{
+	Name:       "timestampedThing",
+	Timestamps: true,
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+	},
+}
+
+var versionedThing = types.ThingConfig{
+	Name:      "versionedThing",
+	Versioned: true,
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+	},
+}
+
+var searchableThing = types.ThingConfig{
+	Name:         "searchableThing",
+	SearchConfig: "english",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"title": {
+			Name:       "title",
+			Type:       types.STRING,
+			Searchable: true,
+		},
+		"description": {
+			Name:       "description",
+			Type:       types.STRING,
+			Searchable: true,
+		},
+		"number": {
+			Name: "number",
+			Type: types.NUMBER,
+		},
+	},
+}
+
+var securedThing = types.ThingConfig{
+	Name: "securedThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+		"secret": {
+			Name: "secret",
+			Type: types.STRING,
+			Access: types.FieldAccess{
+				Hidden: true,
+			},
+		},
+		"status": {
+			Name: "status",
+			Type: types.STRING,
+			Access: types.FieldAccess{
+				Permissions: []types.FieldPermission{types.READ},
+				Roles: map[string][]types.FieldPermission{
+					"admin": {types.READ, types.INSERT, types.UPDATE},
+				},
+			},
+		},
+	},
+}
+
+var typedThing = types.ThingConfig{
+	Name: "typedThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"varchar": {
+			Name:      "varchar",
+			Type:      types.STRING,
+			MaxLength: 50,
+		},
+		"integer": {
+			Name: "integer",
+			Type: types.INTEGER,
+		},
+		"bigint": {
+			Name: "bigint",
+			Type: types.BIGINT,
+		},
+		"decimal": {
+			Name:      "decimal",
+			Type:      types.DECIMAL,
+			Precision: 10,
+			Scale:     2,
+		},
+		"timestamp": {
+			Name: "timestamp",
+			Type: types.TIMESTAMP,
+		},
+		"time": {
+			Name: "time",
+			Type: types.TIME,
+		},
+		"uuid": {
+			Name: "uuid",
+			Type: types.UUID,
+		},
+		"metadata": {
+			Name: "metadata",
+			Type: types.JSON,
+		},
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published"},
+		},
+		"tags": {
+			Name:    "tags",
+			Type:    types.ARRAY,
+			ArrayOf: types.STRING,
+		},
+		"scores": {
+			Name:    "scores",
+			Type:    types.ARRAY,
+			ArrayOf: types.NUMBER,
+		},
+	},
+}
+
+var memberThing = types.ThingConfig{
+	Name: "memberThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+		"groups": {
+			Name: "groups",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.MANY_TO_MANY,
+				OtherThingName: "groupThing",
+				OtherFieldName: "members",
+			},
+		},
+	},
+}
+
+var groupThing = types.ThingConfig{
+	Name: "groupThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name: "string",
+			Type: types.STRING,
+		},
+		"members": {
+			Name: "members",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.MANY_TO_MANY,
+				OtherThingName: "memberThing",
+				OtherFieldName: "groups",
+			},
+		},
+	},
+}
+
 func TestCreateTableWithAllFieldTypes(t *testing.T) {
 	types.Clear()
 	types.Register(parentThing)
@@ -115,8 +341,8 @@ func TestCreateTableWithAllFieldTypes(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(sqls) != 3 {
-		t.Fatalf("expected 3 queries got: %d", len(sqls))
+	if len(sqls) != 6 {
+		t.Fatalf("expected 6 queries got: %d", len(sqls))
 	}
 
 	sql := sqls[0]
@@ -154,6 +380,17 @@ func TestCreateTableWithAllFieldTypes(t *testing.T) {
 	if sql != expected {
 		t.Fatalf("expected: %s have: %s", expected, sql)
 	}
+
+	expectedIndexes := []string{
+		`CREATE INDEX IF NOT EXISTS "parent_thing_thing_id_idx" ON "parent_thing" ("thing_id")`,
+		`CREATE INDEX IF NOT EXISTS "child_thing_many_to_one_id_idx" ON "child_thing" ("many_to_one_id")`,
+		`CREATE INDEX IF NOT EXISTS "child_thing_thing_id_idx" ON "child_thing" ("thing_id")`,
+	}
+	for i, expected := range expectedIndexes {
+		if sqls[3+i] != expected {
+			t.Fatalf("expected: %s have: %s", expected, sqls[3+i])
+		}
+	}
 }
 
 func TestUnregisteredFieldTypeThing(t *testing.T) {
@@ -174,3 +411,455 @@ thingConfig: otherThing doesn't exists`
 		t.Fatalf("expected error: %s got: %s", expectedError, err)
 	}
 }
+
+func TestCreateTableAssignedToUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	generator := generators.CreateTable{
+		ThingName: ownedThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "owned_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT,
+  "owner_id" TEXT NOT NULL
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableOwnerColumnCollision(t *testing.T) {
+	types.Clear()
+	types.Register(types.ThingConfig{
+		Name: "collidingThing",
+		Constraints: types.ThingConstraints{
+			AssignedToUser: true,
+		},
+		Fields: map[string]types.FieldConfig{
+			"ownerId": {
+				Name: "ownerId",
+				Type: types.STRING,
+			},
+		},
+	})
+
+	generator := generators.CreateTable{
+		ThingName: "collidingThing",
+	}
+	_, err := generator.GetSql()
+
+	expectedError := "field: ownerId collides with system column: owner_id"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestCreateTableWithConstraints(t *testing.T) {
+	types.Clear()
+	types.Register(types.ThingConfig{
+		Name: "constrainedThing",
+		Constraints: types.ThingConstraints{
+			Unique: [][]string{{"string", "number"}},
+		},
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {
+				Name: "primaryKey",
+				Type: types.PRIMARY_KEY,
+			},
+			"string": {
+				Name:    "string",
+				Type:    types.STRING,
+				NotNull: true,
+				Unique:  true,
+			},
+			"number": {
+				Name:    "number",
+				Type:    types.NUMBER,
+				Default: "0",
+				Check:   `"number" >= 0`,
+			},
+		},
+	})
+
+	generator := generators.CreateTable{
+		ThingName: "constrainedThing",
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "constrained_thing" (
+  "number" NUMERIC(18, 4) DEFAULT 0 CHECK ("number" >= 0),
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT NOT NULL UNIQUE,
+  UNIQUE ("string", "number")
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableUniqueWithUnknownField(t *testing.T) {
+	types.Clear()
+	types.Register(types.ThingConfig{
+		Name: "constrainedThing",
+		Constraints: types.ThingConstraints{
+			Unique: [][]string{{"unknown"}},
+		},
+		Fields: map[string]types.FieldConfig{
+			"primaryKey": {
+				Name: "primaryKey",
+				Type: types.PRIMARY_KEY,
+			},
+		},
+	})
+
+	generator := generators.CreateTable{
+		ThingName: "constrainedThing",
+	}
+	_, err := generator.GetSql()
+
+	expectedError := "field: unknown not in thing: constrainedThing"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestCreateTableWithExtendedFieldTypes(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	generator := generators.CreateTable{
+		ThingName: typedThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "typed_thing" (
+  "bigint" BIGINT,
+  "decimal" NUMERIC(10, 2),
+  "integer" INTEGER,
+  "metadata" JSONB,
+  "primary_key" SERIAL PRIMARY KEY,
+  "scores" NUMERIC[],
+  "status" TEXT CONSTRAINT "status_enum_check" CHECK ("status" IN ('draft', 'published')),
+  "tags" TEXT[],
+  "time" TIME,
+  "timestamp" TIMESTAMP WITH TIME ZONE,
+  "uuid" UUID,
+  "varchar" VARCHAR(50)
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableSqlite(t *testing.T) {
+	types.Clear()
+	thing := typedThing
+	thing.Timestamps = true
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+		Dialect:   types.SQLITE,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "typed_thing" (
+  "bigint" BIGINT,
+  "decimal" NUMERIC(10, 2),
+  "integer" INTEGER,
+  "metadata" TEXT,
+  "primary_key" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "scores" TEXT,
+  "status" TEXT CONSTRAINT "status_enum_check" CHECK ("status" IN ('draft', 'published')),
+  "tags" TEXT,
+  "time" TEXT,
+  "timestamp" TEXT,
+  "uuid" TEXT,
+  "varchar" VARCHAR(50),
+  "created_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableSqliteSearchable(t *testing.T) {
+	types.Clear()
+	types.Register(searchableThing)
+
+	generator := generators.CreateTable{
+		ThingName: searchableThing.Name,
+		Dialect:   types.SQLITE,
+	}
+	_, err := generator.GetSql()
+	if err == nil {
+		t.Fatal("expected error for searchable fields in sqlite")
+	}
+}
+
+func TestCreateTableManyToMany(t *testing.T) {
+	types.Clear()
+	types.Register(memberThing)
+	types.Register(groupThing)
+
+	generator := generators.CreateTable{
+		ThingName: memberThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 4 {
+		t.Fatalf("expected 4 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "member_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+
+	expected = `CREATE TABLE IF NOT EXISTS "group_thing_members" (
+  "group_thing_id" INTEGER NOT NULL REFERENCES "group_thing" ("primary_key") ON DELETE CASCADE,
+  "member_thing_id" INTEGER NOT NULL REFERENCES "member_thing" ("primary_key") ON DELETE CASCADE,
+  PRIMARY KEY ("group_thing_id", "member_thing_id")
+)`
+	if sqls[2] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[2])
+	}
+
+	expected = `CREATE INDEX IF NOT EXISTS "group_thing_members_member_thing_id_idx" ON "group_thing_members" ("member_thing_id")`
+	if sqls[3] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[3])
+	}
+}
+
+func TestCreateTableManyToManyWithSchema(t *testing.T) {
+	registry := &types.Registry{Schema: "v2"}
+	registry.Register(memberThing)
+	registry.Register(groupThing)
+
+	generator := generators.CreateTable{
+		ThingName: memberThing.Name,
+		Registry:  registry,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 5 {
+		t.Fatalf("expected 5 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE SCHEMA IF NOT EXISTS "v2"`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+
+	expected = `CREATE TABLE IF NOT EXISTS "v2"."member_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT
+)`
+	if sqls[1] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[1])
+	}
+
+	expected = `CREATE TABLE IF NOT EXISTS "v2"."group_thing_members" (
+  "group_thing_id" INTEGER NOT NULL REFERENCES "v2"."group_thing" ("primary_key") ON DELETE CASCADE,
+  "member_thing_id" INTEGER NOT NULL REFERENCES "v2"."member_thing" ("primary_key") ON DELETE CASCADE,
+  PRIMARY KEY ("group_thing_id", "member_thing_id")
+)`
+	if sqls[3] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[3])
+	}
+
+	expected = `CREATE INDEX IF NOT EXISTS "group_thing_members_member_thing_id_idx" ON "v2"."group_thing_members" ("member_thing_id")`
+	if sqls[4] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[4])
+	}
+}
+
+func TestCreateTableWithIndexes(t *testing.T) {
+	types.Clear()
+	thing := typedThing
+	thing.Indexes = []types.IndexConfig{
+		{
+			Fields: []types.IndexField{{Name: "integer"}, {Name: "timestamp", Descending: true}},
+		},
+		{
+			Name:   "typed_thing_active_uuid",
+			Fields: []types.IndexField{{Name: "uuid"}},
+			Unique: true,
+			Where:  "status = published AND integer > 0",
+		},
+	}
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 3 {
+		t.Fatalf("expected 3 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE INDEX IF NOT EXISTS "typed_thing_integer_timestamp_idx" ON "typed_thing" ("integer", "timestamp" DESC)`
+	if sqls[1] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[1])
+	}
+
+	expected = `CREATE UNIQUE INDEX IF NOT EXISTS "typed_thing_active_uuid" ON "typed_thing" ("uuid") WHERE "status" = 'published' AND COALESCE("integer", 0) > 0`
+	if sqls[2] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[2])
+	}
+}
+
+func TestCreateTableSoftDelete(t *testing.T) {
+	types.Clear()
+	types.Register(softDeletedThing)
+
+	generator := generators.CreateTable{
+		ThingName: softDeletedThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "soft_deleted_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT,
+  "deleted_at" TIMESTAMP WITH TIME ZONE
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableTimestamps(t *testing.T) {
+	types.Clear()
+	types.Register(timestampedThing)
+
+	generator := generators.CreateTable{
+		ThingName: timestampedThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "timestamped_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableVersioned(t *testing.T) {
+	types.Clear()
+	types.Register(versionedThing)
+
+	generator := generators.CreateTable{
+		ThingName: versionedThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "versioned_thing" (
+  "primary_key" SERIAL PRIMARY KEY,
+  "string" TEXT,
+  "version" INTEGER NOT NULL DEFAULT 1
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+}
+
+func TestCreateTableSearchable(t *testing.T) {
+	types.Clear()
+	types.Register(searchableThing)
+
+	generator := generators.CreateTable{
+		ThingName: searchableThing.Name,
+	}
+	sqls, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sqls) != 2 {
+		t.Fatalf("expected 2 queries got: %d", len(sqls))
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS "searchable_thing" (
+  "description" TEXT,
+  "number" NUMERIC(18, 4),
+  "primary_key" SERIAL PRIMARY KEY,
+  "title" TEXT,
+  "search_vector" tsvector GENERATED ALWAYS AS (to_tsvector('english', coalesce("description", '') || ' ' || coalesce("title", ''))) STORED
+)`
+	if sqls[0] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[0])
+	}
+
+	expected = `CREATE INDEX IF NOT EXISTS "searchable_thing_search_vector_idx" ON "searchable_thing" USING GIN ("search_vector")`
+	if sqls[1] != expected {
+		t.Fatalf("expected: %s have: %s", expected, sqls[1])
+	}
+}
+
+func TestCreateTableSearchableHiddenField(t *testing.T) {
+	types.Clear()
+	thing := searchableThing
+	thing.Fields = maps.Clone(searchableThing.Fields)
+	description := thing.Fields["description"]
+	description.Access.Hidden = true
+	thing.Fields["description"] = description
+	types.Register(thing)
+
+	generator := generators.CreateTable{
+		ThingName: thing.Name,
+	}
+	_, err := generator.GetSql()
+
+	expectedError := "hidden field: description cannot be searchable"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
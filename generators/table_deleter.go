@@ -0,0 +1,64 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"json2sql/types"
+)
+
+type DeleteFromTable struct {
+	ThingName string
+	Values    map[string]any
+	Context   context.Context
+	Registry  *types.Registry
+	thing     types.ThingConfig
+}
+
+func (dft *DeleteFromTable) GetSql() (string, error) {
+	var errs []error
+	thing, err := dft.Registry.Get(dft.ThingName)
+	if err != nil {
+		return "", err
+	}
+	dft.thing = thing
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := dft.Values[primaryKey.Name]; !ok {
+		return "", fmt.Errorf("primary key: %s is required to delete thing: %s", primaryKey.Name, thing.Name)
+	}
+
+	whereString := fmt.Sprintf(`"%s" = :%s`, primaryKey.GetColumnName(), primaryKey.GetValueName())
+	if thing.Constraints.AssignedToUser {
+		_, err := thing.GetOwnerId(dft.Context)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		whereString += fmt.Sprintf(` AND "%s" = :%s`, types.OWNER_COLUMN_NAME, types.OWNER_VALUE_NAME)
+	}
+
+	if thing.SoftDelete {
+		whereString += fmt.Sprintf(` AND "%s" IS NULL`, types.DELETED_AT_COLUMN_NAME)
+		setString := fmt.Sprintf(`"%s" = now()`, types.DELETED_AT_COLUMN_NAME)
+		if thing.Timestamps {
+			setString += fmt.Sprintf(`, "%s" = now()`, types.UPDATED_AT_COLUMN_NAME)
+		}
+		query := fmt.Sprintf(`UPDATE %s
+SET %s
+WHERE %s`, thing.GetTableName(), setString, whereString)
+		return query, errors.Join(errs...)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s
+WHERE %s`, thing.GetTableName(), whereString)
+
+	return query, errors.Join(errs...)
+}
+
+func (dft *DeleteFromTable) GetValues() map[string]any {
+	return getNamedValues(dft.Registry, dft.ThingName, dft.Values, dft.Context)
+}
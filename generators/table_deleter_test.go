@@ -0,0 +1,82 @@
+package generators_test
+
+import (
+	"context"
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.DeleteFromTable{
+		ThingName: parentThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `DELETE FROM "parent_thing"
+WHERE "primary_key" = :primaryKey`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestDeleteAssignedToUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	generator := generators.DeleteFromTable{
+		ThingName: ownedThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+		},
+		Context: types.WithUserId(context.Background(), "user1"),
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `DELETE FROM "owned_thing"
+WHERE "primary_key" = :primaryKey AND "owner_id" = :_ownerId`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	types.Clear()
+	types.Register(softDeletedThing)
+
+	generator := generators.DeleteFromTable{
+		ThingName: softDeletedThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "soft_deleted_thing"
+SET "deleted_at" = now()
+WHERE "primary_key" = :primaryKey AND "deleted_at" IS NULL`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
@@ -0,0 +1,73 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"json2sql/types"
+	"strings"
+)
+
+type SelectByIds struct {
+	ThingName           string
+	FieldName           string
+	Ids                 []any
+	FieldsMap           map[string]any
+	Context             context.Context
+	SkipForbiddenFields bool
+	Registry            *types.Registry
+	selectFromTable     SelectFromTable
+}
+
+func (sbi *SelectByIds) GetSql() (string, error) {
+	sbi.selectFromTable = SelectFromTable{
+		ThingName:           sbi.ThingName,
+		FieldsMap:           sbi.FieldsMap,
+		Context:             sbi.Context,
+		SkipForbiddenFields: sbi.SkipForbiddenFields,
+		Registry:            sbi.Registry,
+	}
+	err := sbi.selectFromTable.prepareSelect()
+	if err != nil {
+		return "", err
+	}
+
+	s := &sbi.selectFromTable
+	field, err := s.thing.GetPrimaryKey()
+	if sbi.FieldName != "" {
+		field, err = s.thing.GetField(sbi.FieldName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	idsValue, err := getIdsValue(sbi.Ids)
+	if err != nil {
+		return "", err
+	}
+
+	s.whereValues = append(s.whereValues, idsValue)
+	whereString := fmt.Sprintf(`%s."%s" = ANY($%d)`, mainTableAlias, field.GetColumnName(), len(s.whereValues))
+	if s.whereString != "" {
+		whereString += fmt.Sprintf(" AND (%s)", strings.TrimSpace(s.whereString))
+	}
+
+	columnsString := fmt.Sprintf(`%s."%s" as "%s"`, mainTableAlias, field.GetColumnName(), types.RELATED_ID_ALIAS)
+	if s.columnsString != "" {
+		columnsString += ", " + s.columnsString
+	}
+
+	query := fmt.Sprintf("SELECT %s\n"+
+		"FROM %s %s\n"+
+		"WHERE %s",
+		columnsString, s.thing.GetTableName(), mainTableAlias, whereString)
+
+	if s.orderString != "" {
+		query += fmt.Sprintf("\nORDER BY %s", s.orderString)
+	}
+
+	return query, nil
+}
+
+func (sbi *SelectByIds) GetWhereValues() []any {
+	return sbi.selectFromTable.GetWhereValues()
+}
@@ -0,0 +1,71 @@
+package generators_test
+
+import (
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSelectByIds(t *testing.T) {
+	types.Clear()
+	types.Register(childThing)
+
+	s := generators.SelectByIds{
+		ThingName: childThing.Name,
+		FieldName: "manyToOne",
+		Ids:       []any{1, 2},
+		FieldsMap: map[string]any{
+			"string":   "",
+			"_where":   "string = test",
+			"_orderBy": "string desc",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."many_to_one_id" as "_relatedId", t."string" as "string"
+FROM "child_thing" t
+WHERE t."many_to_one_id" = ANY($2) AND (t."string" = $1)
+ORDER BY t."string" DESC`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	ids, ok := whereValues[1].(pq.Int64Array)
+	if !ok || len(ids) != 2 {
+		t.Fatalf("expected ids array got: %v", whereValues[1])
+	}
+}
+
+func TestSelectByIdsDefaultsToPrimaryKey(t *testing.T) {
+	types.Clear()
+	types.Register(otherThing)
+
+	s := generators.SelectByIds{
+		ThingName: otherThing.Name,
+		Ids:       []any{1},
+		FieldsMap: map[string]any{
+			"string": "",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."primary_key" as "_relatedId", t."string" as "string"
+FROM "other_thing" t
+WHERE t."primary_key" = ANY($1)`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+}
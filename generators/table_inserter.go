@@ -1,9 +1,12 @@
 package generators
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"json2sql/types"
+	"json2sql/validators"
 	"sort"
 	"strings"
 
@@ -11,9 +14,13 @@ import (
 )
 
 type InsertIntoTable struct {
-	ThingName string
-	Values    map[string]any
-	thing     types.ThingConfig
+	ThingName           string
+	Values              map[string]any
+	Context             context.Context
+	SkipForbiddenFields bool
+	ReturningKey        bool
+	Registry            *types.Registry
+	thing               types.ThingConfig
 }
 
 func (iit *InsertIntoTable) GetValuesFieldNames() []string {
@@ -24,12 +31,15 @@ func (iit *InsertIntoTable) GetValuesFieldNames() []string {
 
 func (iit *InsertIntoTable) GetSql() (string, error) {
 	var errs []error
-	thing, err := types.Get(iit.ThingName)
+	thing, err := iit.Registry.Get(iit.ThingName)
 	if err != nil {
 		return "", err
 	}
+	iit.thing = thing
 	intoString := ""
 	valuesString := ""
+	validValues := map[string]any{}
+	systemColumns := getSystemColumnCreateStrings(thing)
 
 	for _, fieldName := range iit.GetValuesFieldNames() {
 		field, err := thing.GetField(fieldName)
@@ -45,20 +55,136 @@ func (iit *InsertIntoTable) GetSql() (string, error) {
 			continue
 		}
 
-		valueFieldName := field.Name
-		if field.Type == types.RELATION || field.Type == types.THING {
-			valueFieldName = field.Name + "Id"
+		if _, ok := systemColumns[field.GetColumnName()]; ok {
+			errs = append(errs, fmt.Errorf("field: %s is managed automatically", field.Name))
+			continue
+		}
+
+		if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			errs = append(errs, fmt.Errorf("relation: %s cannot be inserted", field.Name))
+			continue
+		}
+
+		accessible, err := isFieldAccessible(field, types.INSERT, iit.Context, iit.SkipForbiddenFields)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !accessible {
+			continue
 		}
+		validValues[fieldName] = iit.Values[fieldName]
 
 		intoString += fmt.Sprintf(`"%s", `, field.GetColumnName())
-		valuesString += fmt.Sprintf(":%s, ", valueFieldName)
+		valuesString += fmt.Sprintf(":%s, ", field.GetValueName())
+	}
+
+	validator := validators.Validator{}
+	err = validator.Validate(thing, validValues)
+	if err == nil {
+		err = checkArrayValues(thing, validValues)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if thing.Constraints.AssignedToUser {
+		_, err := thing.GetOwnerId(iit.Context)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		intoString += fmt.Sprintf(`"%s", `, types.OWNER_COLUMN_NAME)
+		valuesString += fmt.Sprintf(":%s, ", types.OWNER_VALUE_NAME)
+	}
+
+	if thing.Timestamps {
+		intoString += fmt.Sprintf(`"%s", "%s", `, types.CREATED_AT_COLUMN_NAME, types.UPDATED_AT_COLUMN_NAME)
+		valuesString += "now(), now(), "
+	}
+
+	if thing.Versioned {
+		intoString += fmt.Sprintf(`"%s", `, types.VERSION_COLUMN_NAME)
+		valuesString += "1, "
+	}
+
+	if intoString == "" && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("no fields to insert in thing: %s", thing.Name))
 	}
 
 	intoString = strings.TrimSuffix(intoString, ", ")
 	valuesString = strings.TrimSuffix(valuesString, ", ")
 
-	query := fmt.Sprintf(`INSERT INTO "%s" (`+intoString+`)
+	query := fmt.Sprintf(`INSERT INTO %s (`+intoString+`)
 VALUES (`+valuesString+`)`, thing.GetTableName())
 
+	if iit.ReturningKey {
+		primaryKey, err := thing.GetPrimaryKey()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		query += fmt.Sprintf("\nRETURNING \"%s\"", primaryKey.GetColumnName())
+	}
+
 	return query, errors.Join(errs...)
 }
+
+func (iit *InsertIntoTable) GetValues() map[string]any {
+	return getNamedValues(iit.Registry, iit.ThingName, iit.Values, iit.Context)
+}
+
+// checkArrayValues returns the errors of converting array values, which
+// getNamedValues can't return.
+func checkArrayValues(thing types.ThingConfig, values map[string]any) error {
+	var errs []error
+	for _, field := range thing.GetFields() {
+		value, ok := values[field.Name]
+		if !ok || value == nil || field.Type != types.ARRAY {
+			continue
+		}
+		_, err := field.GetArrayValue(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field: %s %w", field.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func getNamedValues(registry *types.Registry, thingName string, values map[string]any, ctx context.Context) map[string]any {
+	result := maps.Clone(values)
+	if result == nil {
+		result = map[string]any{}
+	}
+
+	thing, err := registry.Get(thingName)
+	if err != nil {
+		return result
+	}
+
+	for _, field := range thing.GetFields() {
+		value, ok := result[field.Name]
+		if ok && field.GetValueName() != field.Name {
+			result[field.GetValueName()] = value
+		}
+		if ok && value != nil && field.Type == types.JSON {
+			data, err := json.Marshal(value)
+			if err == nil {
+				result[field.Name] = string(data)
+			}
+		}
+		if ok && value != nil && field.Type == types.ARRAY {
+			arrayValue, err := field.GetArrayValue(value)
+			if err == nil {
+				result[field.Name] = arrayValue
+			}
+		}
+	}
+
+	if thing.Constraints.AssignedToUser {
+		userId, ok := types.GetUserId(ctx)
+		if ok {
+			result[types.OWNER_VALUE_NAME] = userId
+		}
+	}
+
+	return result
+}
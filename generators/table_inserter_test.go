@@ -1,12 +1,22 @@
 package generators_test
 
 import (
+	"context"
+	"errors"
 	"json2sql/generators"
+	"json2sql/types"
+	"json2sql/validators"
 	"testing"
 	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/exp/maps"
 )
 
 func TestInsert(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
 	generator := generators.InsertIntoTable{
 		ThingName: parentThing.Name,
 		Values: map[string]any{
@@ -29,3 +39,350 @@ VALUES (:boolean, :date, :number, :string)`
 		t.Fatalf("expected: %s got: %s", expected, sql)
 	}
 }
+
+func TestInsertReturningKey(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName:    parentThing.Name,
+		Values:       map[string]any{"string": "test"},
+		ReturningKey: true,
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "parent_thing" ("string")
+VALUES (:string)
+RETURNING "primary_key"`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestInsertAssignedToUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: ownedThing.Name,
+		Values: map[string]any{
+			"string": "test",
+		},
+		Context: types.WithUserId(context.Background(), "user1"),
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "owned_thing" ("string", "owner_id")
+VALUES (:string, :_ownerId)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	values := generator.GetValues()
+	if values["_ownerId"] != "user1" {
+		t.Fatalf("expected: %s got: %v", "user1", values["_ownerId"])
+	}
+}
+
+func TestInsertAssignedToUserWithoutUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: ownedThing.Name,
+		Values: map[string]any{
+			"string": "test",
+		},
+	}
+
+	_, err := generator.GetSql()
+
+	expectedError := "thing: ownedThing is assigned to user but no user id in context"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestInsertForbiddenField(t *testing.T) {
+	types.Clear()
+	types.Register(securedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: securedThing.Name,
+		Values: map[string]any{
+			"string": "test",
+			"status": "published",
+		},
+	}
+
+	_, err := generator.GetSql()
+
+	expectedError := "field: status is not allowed to insert"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+
+	generator.SkipForbiddenFields = true
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "secured_thing" ("string")
+VALUES (:string)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	generator.Values = map[string]any{"status": "published"}
+	_, err = generator.GetSql()
+
+	expectedError = "no fields to insert in thing: securedThing"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+
+	generator.Values = map[string]any{"string": "test", "status": "published"}
+	generator.SkipForbiddenFields = false
+	generator.Context = types.WithRole(context.Background(), "admin")
+	sql, err = generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `INSERT INTO "secured_thing" ("status", "string")
+VALUES (:status, :string)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestInsertInvalidValue(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: parentThing.Name,
+		Values: map[string]any{
+			"string": 1,
+			"number": "one",
+		},
+	}
+
+	_, err := generator.GetSql()
+
+	var validationError *validators.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected validation error got: %v", err)
+	}
+
+	if len(validationError.Violations) != 2 {
+		t.Fatalf("expected 2 violations got: %v", validationError.Violations)
+	}
+}
+
+func TestInsertJsonValue(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: typedThing.Name,
+		Values: map[string]any{
+			"metadata": map[string]any{
+				"address": map[string]any{"city": "Oslo"},
+			},
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "typed_thing" ("metadata")
+VALUES (:metadata)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	expectedValue := `{"address":{"city":"Oslo"}}`
+	if generator.GetValues()["metadata"] != expectedValue {
+		t.Fatalf("expected: %s got: %v", expectedValue, generator.GetValues()["metadata"])
+	}
+}
+
+func TestInsertArrayValue(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: typedThing.Name,
+		Values: map[string]any{
+			"tags": []any{"red", "blue"},
+		},
+	}
+
+	_, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, ok := generator.GetValues()["tags"].(pq.StringArray)
+	if !ok || len(tags) != 2 || tags[0] != "red" || tags[1] != "blue" {
+		t.Fatalf("expected: [red blue] got: %v", generator.GetValues()["tags"])
+	}
+
+	thing := typedThing
+	thing.Fields = maps.Clone(typedThing.Fields)
+	thing.Fields["tags"] = types.FieldConfig{Name: "tags", Type: types.ARRAY, ArrayOf: types.DECIMAL}
+	types.Clear()
+	types.Register(thing)
+	generator.Values = map[string]any{
+		"tags": []any{"1.50"},
+	}
+
+	_, err = generator.GetSql()
+
+	expectedError := "field: tags cannot be array of DECIMAL"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestInsertRelations(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: parentThing.Name,
+		Values: map[string]any{
+			"thing": 5,
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "parent_thing" ("thing_id")
+VALUES (:thingId)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	if generator.GetValues()["thingId"] != 5 {
+		t.Fatalf("expected: 5 got: %v", generator.GetValues()["thingId"])
+	}
+
+	generator.Values = map[string]any{
+		"oneToMany": []any{1},
+	}
+
+	_, err = generator.GetSql()
+
+	expectedError := "relation: oneToMany cannot be inserted"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestInsertTimestamps(t *testing.T) {
+	types.Clear()
+	types.Register(timestampedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: timestampedThing.Name,
+		Values: map[string]any{
+			"string": "test",
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "timestamped_thing" ("string", "created_at", "updated_at")
+VALUES (:string, now(), now())`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestInsertManagedField(t *testing.T) {
+	types.Clear()
+	thing := timestampedThing
+	thing.Fields = map[string]types.FieldConfig{
+		"createdAt": {
+			Name: "createdAt",
+			Type: types.TIMESTAMP,
+		},
+	}
+	types.Register(thing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: thing.Name,
+		Values: map[string]any{
+			"createdAt": time.Now(),
+		},
+	}
+
+	_, err := generator.GetSql()
+
+	expectedError := "field: createdAt is managed automatically"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestInsertVersioned(t *testing.T) {
+	types.Clear()
+	types.Register(versionedThing)
+
+	generator := generators.InsertIntoTable{
+		ThingName: versionedThing.Name,
+		Values: map[string]any{
+			"string": "test",
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "versioned_thing" ("string", "version")
+VALUES (:string, 1)`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
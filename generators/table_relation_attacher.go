@@ -0,0 +1,143 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"json2sql/types"
+)
+
+const (
+	idValueName         = "id"
+	relatedIdsValueName = "relatedIds"
+	otherTableAlias     = "o"
+)
+
+type AttachRelated struct {
+	ThingName  string
+	FieldName  string
+	Id         any
+	RelatedIds []any
+	Context    context.Context
+	Registry   *types.Registry
+}
+
+func (ar *AttachRelated) GetSql() (string, error) {
+	var errs []error
+	thing, field, otherThing, err := getManyToManyRelation(ar.Registry, ar.ThingName, ar.FieldName)
+	if err != nil {
+		return "", err
+	}
+	joinTable := thing.GetJoinTable(field, otherThing)
+
+	thingPrimaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	otherPrimaryKey, err := otherThing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	whereString := fmt.Sprintf(`%s."%s" = :%s AND %s."%s" = ANY(CAST(:%s AS INTEGER[]))`,
+		mainTableAlias, thingPrimaryKey.GetColumnName(), idValueName,
+		otherTableAlias, otherPrimaryKey.GetColumnName(), relatedIdsValueName)
+
+	if thing.Constraints.AssignedToUser {
+		_, err := thing.GetOwnerId(ar.Context)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		whereString += fmt.Sprintf(` AND %s."%s" = :%s`, mainTableAlias, types.OWNER_COLUMN_NAME, types.OWNER_VALUE_NAME)
+	}
+
+	if otherThing.Constraints.AssignedToUser {
+		_, err := otherThing.GetOwnerId(ar.Context)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		whereString += fmt.Sprintf(` AND %s."%s" = :%s`, otherTableAlias, types.OWNER_COLUMN_NAME, types.OWNER_VALUE_NAME)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s ("%s", "%s")
+SELECT %s."%s", %s."%s"
+FROM %s %s, %s %s
+WHERE %s
+ON CONFLICT DO NOTHING`,
+		joinTable.GetTableName(), joinTable.ThingColumn, joinTable.OtherColumn,
+		mainTableAlias, thingPrimaryKey.GetColumnName(), otherTableAlias, otherPrimaryKey.GetColumnName(),
+		thing.GetTableName(), mainTableAlias, otherThing.GetTableName(), otherTableAlias,
+		whereString)
+
+	return query, errors.Join(errs...)
+}
+
+func (ar *AttachRelated) GetValues() map[string]any {
+	return getRelationValues(ar.Id, ar.RelatedIds, ar.Context)
+}
+
+type DetachRelated struct {
+	ThingName  string
+	FieldName  string
+	Id         any
+	RelatedIds []any
+	Context    context.Context
+	Registry   *types.Registry
+}
+
+func (dr *DetachRelated) GetSql() (string, error) {
+	var errs []error
+	thing, field, otherThing, err := getManyToManyRelation(dr.Registry, dr.ThingName, dr.FieldName)
+	if err != nil {
+		return "", err
+	}
+	joinTable := thing.GetJoinTable(field, otherThing)
+
+	whereString := fmt.Sprintf(`"%s" = :%s AND "%s" = ANY(CAST(:%s AS INTEGER[]))`,
+		joinTable.ThingColumn, idValueName, joinTable.OtherColumn, relatedIdsValueName)
+
+	if thing.Constraints.AssignedToUser {
+		_, err := thing.GetOwnerId(dr.Context)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		thingPrimaryKey, err := thing.GetPrimaryKey()
+		if err != nil {
+			return "", err
+		}
+
+		whereString += fmt.Sprintf(` AND EXISTS (SELECT 1 FROM %s %s WHERE %s."%s" = :%s AND %s."%s" = :%s)`,
+			thing.GetTableName(), mainTableAlias,
+			mainTableAlias, thingPrimaryKey.GetColumnName(), idValueName,
+			mainTableAlias, types.OWNER_COLUMN_NAME, types.OWNER_VALUE_NAME)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s
+WHERE %s`, joinTable.GetTableName(), whereString)
+
+	return query, errors.Join(errs...)
+}
+
+func (dr *DetachRelated) GetValues() map[string]any {
+	return getRelationValues(dr.Id, dr.RelatedIds, dr.Context)
+}
+
+func getRelationValues(id any, relatedIds []any, ctx context.Context) map[string]any {
+	result := map[string]any{
+		idValueName: id,
+	}
+
+	idsValue, err := getIdsValue(relatedIds)
+	if err == nil {
+		result[relatedIdsValueName] = idsValue
+	}
+
+	userId, ok := types.GetUserId(ctx)
+	if ok {
+		result[types.OWNER_VALUE_NAME] = userId
+	}
+
+	return result
+}
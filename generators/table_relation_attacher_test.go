@@ -0,0 +1,65 @@
+package generators_test
+
+import (
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+)
+
+func TestAttachRelated(t *testing.T) {
+	types.Clear()
+	types.Register(memberThing)
+	types.Register(groupThing)
+
+	generator := generators.AttachRelated{
+		ThingName:  groupThing.Name,
+		FieldName:  "members",
+		Id:         1,
+		RelatedIds: []any{2, 3},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "group_thing_members" ("group_thing_id", "member_thing_id")
+SELECT t."primary_key", o."primary_key"
+FROM "group_thing" t, "member_thing" o
+WHERE t."primary_key" = :id AND o."primary_key" = ANY(CAST(:relatedIds AS INTEGER[]))
+ON CONFLICT DO NOTHING`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	values := generator.GetValues()
+	if values["id"] != 1 || values["relatedIds"] == nil {
+		t.Fatalf("expected id and related ids got: %v", values)
+	}
+}
+
+func TestDetachRelated(t *testing.T) {
+	types.Clear()
+	types.Register(memberThing)
+	types.Register(groupThing)
+
+	generator := generators.DetachRelated{
+		ThingName:  memberThing.Name,
+		FieldName:  "groups",
+		Id:         1,
+		RelatedIds: []any{2},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `DELETE FROM "group_thing_members"
+WHERE "member_thing_id" = :id AND "group_thing_id" = ANY(CAST(:relatedIds AS INTEGER[]))`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
@@ -0,0 +1,109 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"json2sql/types"
+	"strings"
+)
+
+const joinTableAlias = "j"
+
+type SelectRelated struct {
+	ThingName           string
+	FieldName           string
+	Ids                 []any
+	FieldsMap           map[string]any
+	Context             context.Context
+	SkipForbiddenFields bool
+	Registry            *types.Registry
+	selectFromTable     SelectFromTable
+}
+
+func (sr *SelectRelated) GetSql() (string, error) {
+	thing, field, otherThing, err := getManyToManyRelation(sr.Registry, sr.ThingName, sr.FieldName)
+	if err != nil {
+		return "", err
+	}
+	joinTable := thing.GetJoinTable(field, otherThing)
+
+	otherPrimaryKey, err := otherThing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	sr.selectFromTable = SelectFromTable{
+		ThingName:           otherThing.Name,
+		FieldsMap:           sr.FieldsMap,
+		Context:             sr.Context,
+		SkipForbiddenFields: sr.SkipForbiddenFields,
+		Registry:            sr.Registry,
+	}
+	err = sr.selectFromTable.prepareSelect()
+	if err != nil {
+		return "", err
+	}
+
+	idsValue, err := getIdsValue(sr.Ids)
+	if err != nil {
+		return "", err
+	}
+
+	s := &sr.selectFromTable
+	s.whereValues = append(s.whereValues, idsValue)
+	whereString := fmt.Sprintf(`%s."%s" = ANY($%d)`, joinTableAlias, joinTable.ThingColumn, len(s.whereValues))
+	if s.whereString != "" {
+		whereString += fmt.Sprintf(" AND (%s)", strings.TrimSpace(s.whereString))
+	}
+
+	columnsString := fmt.Sprintf(`%s."%s" as "%s"`, joinTableAlias, joinTable.ThingColumn, types.RELATED_ID_ALIAS)
+	if s.columnsString != "" {
+		columnsString += ", " + s.columnsString
+	}
+
+	query := fmt.Sprintf("SELECT %s\n"+
+		"FROM %s %s\n"+
+		"JOIN %s %s ON %s.\"%s\" = %s.\"%s\"\n"+
+		"WHERE %s",
+		columnsString, otherThing.GetTableName(), mainTableAlias,
+		joinTable.GetTableName(), joinTableAlias, joinTableAlias, joinTable.OtherColumn, mainTableAlias, otherPrimaryKey.GetColumnName(),
+		whereString)
+
+	if s.orderString != "" {
+		query += fmt.Sprintf("\nORDER BY %s", s.orderString)
+	}
+
+	return query, nil
+}
+
+func (sr *SelectRelated) GetWhereValues() []any {
+	return sr.selectFromTable.GetWhereValues()
+}
+
+func getManyToManyRelation(registry *types.Registry, thingName string, fieldName string) (types.ThingConfig, types.FieldConfig, types.ThingConfig, error) {
+	thing, err := registry.Get(thingName)
+	if err != nil {
+		return types.ThingConfig{}, types.FieldConfig{}, types.ThingConfig{}, err
+	}
+
+	field, err := thing.GetField(fieldName)
+	if err != nil {
+		return types.ThingConfig{}, types.FieldConfig{}, types.ThingConfig{}, err
+	}
+
+	if field.Type != types.RELATION || field.Relation.Type != types.MANY_TO_MANY {
+		return types.ThingConfig{}, types.FieldConfig{}, types.ThingConfig{}, fmt.Errorf("field: %s is not many to many relation", field.Name)
+	}
+
+	otherThing, err := registry.Get(field.Relation.OtherThingName)
+	if err != nil {
+		return types.ThingConfig{}, types.FieldConfig{}, types.ThingConfig{}, err
+	}
+
+	return thing, field, otherThing, nil
+}
+
+func getIdsValue(ids []any) (any, error) {
+	idsField := types.FieldConfig{Name: "ids", Type: types.ARRAY, ArrayOf: types.INTEGER}
+	return idsField.GetArrayValue(ids)
+}
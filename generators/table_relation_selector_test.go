@@ -0,0 +1,92 @@
+package generators_test
+
+import (
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSelectRelated(t *testing.T) {
+	types.Clear()
+	types.Register(memberThing)
+	types.Register(groupThing)
+
+	s := generators.SelectRelated{
+		ThingName: memberThing.Name,
+		FieldName: "groups",
+		Ids:       []any{1, 2},
+		FieldsMap: map[string]any{
+			"string": "",
+			"_where": "string = test",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT j."member_thing_id" as "_relatedId", t."string" as "string"
+FROM "group_thing" t
+JOIN "group_thing_members" j ON j."group_thing_id" = t."primary_key"
+WHERE j."member_thing_id" = ANY($2) AND (t."string" = $1)`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	ids, ok := whereValues[1].(pq.Int64Array)
+	if !ok || len(ids) != 2 {
+		t.Fatalf("expected ids array got: %v", whereValues[1])
+	}
+}
+
+func TestSelectRelatedWithSchema(t *testing.T) {
+	registry := &types.Registry{Schema: "v2"}
+	registry.Register(memberThing)
+	registry.Register(groupThing)
+
+	s := generators.SelectRelated{
+		ThingName: memberThing.Name,
+		FieldName: "groups",
+		Ids:       []any{1},
+		FieldsMap: map[string]any{"string": ""},
+		Registry:  registry,
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT j."member_thing_id" as "_relatedId", t."string" as "string"
+FROM "v2"."group_thing" t
+JOIN "v2"."group_thing_members" j ON j."group_thing_id" = t."primary_key"
+WHERE j."member_thing_id" = ANY($1)`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+}
+
+func TestSelectRelatedNotManyToMany(t *testing.T) {
+	types.Clear()
+	types.Register(memberThing)
+
+	s := generators.SelectRelated{
+		ThingName: memberThing.Name,
+		FieldName: "string",
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "field: string is not many to many relation"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
@@ -1,27 +1,31 @@
 package generators
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"json2sql/parsers"
 	"json2sql/types"
 	"strconv"
 	"strings"
 
-	"github.com/iancoleman/strcase"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
 type SelectFromTable struct {
-	ThingName     string
-	FieldsMap     map[string]any
-	Page          uint
-	Count         uint
-	thing         types.ThingConfig
-	columnsString string
-	whereString   string
-	whereValues   []any
+	ThingName           string
+	FieldsMap           map[string]any
+	Page                uint
+	Count               uint
+	Context             context.Context
+	SkipForbiddenFields bool
+	Registry            *types.Registry
+	thing               types.ThingConfig
+	columnsString       string
+	whereString         string
+	whereValues         []any
+	searchValue         string
+	orderString         string
 }
 
 type SelectColumn struct {
@@ -32,23 +36,30 @@ type SelectColumn struct {
 
 const (
 	mainTableAlias = "t"
+	rankOrderName  = "_rank"
 )
 
 func (s *SelectFromTable) GetSql() (string, error) {
 	err := s.prepareSelect()
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 
-	mainTableName := strcase.ToSnake(s.thing.Name)
+	if s.columnsString == "" {
+		return "", fmt.Errorf("no fields to select in thing: %s", s.thing.Name)
+	}
 
 	query := fmt.Sprintf("SELECT %s\n"+
-		"FROM \"%s\" %s", s.columnsString, mainTableName, mainTableAlias)
+		"FROM %s %s", s.columnsString, s.thing.GetTableName(), mainTableAlias)
 
 	if s.whereString != "" {
 		query += fmt.Sprintf("\nWHERE %s", s.whereString)
 	}
 
+	if s.orderString != "" {
+		query += fmt.Sprintf("\nORDER BY %s", s.orderString)
+	}
+
 	if s.Count > 0 {
 		limit := strconv.FormatUint(uint64(s.Count), 10)
 		query += fmt.Sprintf("\nLIMIT %s", limit)
@@ -66,11 +77,16 @@ func (s *SelectFromTable) GetWhereValues() []any {
 }
 
 func (s *SelectFromTable) prepareSelect() error {
-	thing, err := types.Get(s.ThingName)
+	thing, err := s.Registry.Get(s.ThingName)
 	if err != nil {
 		return err
 	}
 	s.thing = thing
+	s.columnsString = ""
+	s.whereString = ""
+	s.whereValues = nil
+	s.searchValue = ""
+	s.orderString = ""
 
 	var errs []error
 	thingConfig := s.thing
@@ -81,6 +97,14 @@ func (s *SelectFromTable) prepareSelect() error {
 	})
 
 	for _, fieldName := range keys {
+		if systemField, ok := thingConfig.GetSystemField(fieldName); ok {
+			s.columnsString += GetColumnString(SelectColumn{
+				columnName:     types.GetSystemColumnName(systemField.Name),
+				aliasName:      fieldName,
+				tableAliasName: mainTableAlias,
+			})
+			continue
+		}
 		if strings.HasPrefix(fieldName, "_") {
 			continue
 		}
@@ -91,6 +115,15 @@ func (s *SelectFromTable) prepareSelect() error {
 			continue
 		}
 
+		accessible, err := isFieldAccessible(fieldConfig, types.READ, s.Context, s.SkipForbiddenFields)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !accessible {
+			continue
+		}
+
 		selectColumn := SelectColumn{
 			columnName:     fieldConfig.GetColumnName(),
 			aliasName:      fieldName,
@@ -101,13 +134,43 @@ func (s *SelectFromTable) prepareSelect() error {
 	}
 
 	s.columnsString = strings.TrimSuffix(s.columnsString, ", ")
+
+	systemWhereStrings := []string{}
+	if thingConfig.Constraints.AssignedToUser {
+		userId, err := thingConfig.GetOwnerId(s.Context)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			s.whereValues = append(s.whereValues, userId)
+			systemWhereStrings = append(systemWhereStrings,
+				fmt.Sprintf(`%s."%s" = $%d`, mainTableAlias, types.OWNER_COLUMN_NAME, len(s.whereValues)))
+		}
+	}
+
+	withDeleted, _ := s.FieldsMap["_withDeleted"].(bool)
+	if thingConfig.SoftDelete && !withDeleted {
+		systemWhereStrings = append(systemWhereStrings,
+			fmt.Sprintf(`%s."%s" IS NULL`, mainTableAlias, types.DELETED_AT_COLUMN_NAME))
+	}
+
 	whereString, err := s.GetWhereString()
 	if err != nil {
 		errs = append(errs, err)
+	} else if len(systemWhereStrings) > 0 && whereString != "" {
+		s.whereString = fmt.Sprintf("%s AND (%s)", strings.Join(systemWhereStrings, " AND "), strings.TrimSpace(whereString))
+	} else if len(systemWhereStrings) > 0 {
+		s.whereString = strings.Join(systemWhereStrings, " AND ")
 	} else {
 		s.whereString = whereString
 	}
 
+	orderString, err := s.GetOrderString()
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		s.orderString = orderString
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -121,7 +184,6 @@ func GetColumnString(column SelectColumn) string {
 }
 
 func (s *SelectFromTable) GetWhereString() (string, error) {
-	result := ""
 	w, ok := s.FieldsMap["_where"]
 
 	if !ok {
@@ -133,57 +195,69 @@ func (s *SelectFromTable) GetWhereString() (string, error) {
 		return "", fmt.Errorf("_where must be string")
 	}
 
-	parser := parsers.Parser{}
-	tokens := parser.Parse(whereValue)
-	if len(tokens) <= 0 {
-		return "", fmt.Errorf("_where is empty")
+	compiler := whereCompiler{
+		thing:      s.thing,
+		context:    s.Context,
+		tableAlias: mainTableAlias,
+		values:     s.whereValues,
 	}
+	result, err := compiler.compile(whereValue)
+	if err != nil {
+		return "", err
+	}
+	s.whereValues = compiler.values
+	s.searchValue = compiler.searchValue
 
-	thing := s.thing
-	for _, token := range tokens {
-		whereField, isField := thing.Fields[token]
-		if isField {
-			if whereField.Type == types.NUMBER {
-				result += fmt.Sprintf(`COALESCE(%s."%s", 0) `, mainTableAlias, whereField.GetColumnName())
-			} else {
-				result += fmt.Sprintf(`%s."%s" `, mainTableAlias, whereField.GetColumnName())
-			}
-			continue
+	return result, nil
+}
+
+func (s *SelectFromTable) GetOrderString() (string, error) {
+	o, ok := s.FieldsMap["_orderBy"]
+	if !ok {
+		return "", nil
+	}
+
+	orderValue, ok := o.(string)
+	if !ok {
+		return "", fmt.Errorf("_orderBy must be string")
+	}
+
+	results := []string{}
+	for _, orderPart := range strings.Split(orderValue, ",") {
+		tokens := strings.Fields(orderPart)
+		if len(tokens) == 0 || len(tokens) > 2 {
+			return "", fmt.Errorf("_orderBy: %s is invalid", orderPart)
 		}
 
-		compareToken := getCompareToken(token)
-		if compareToken != "" {
-			result += compareToken + " "
-			continue
+		direction := ""
+		if len(tokens) == 2 {
+			direction = strings.ToUpper(tokens[1])
+			if direction != "ASC" && direction != "DESC" {
+				return "", fmt.Errorf("_orderBy direction: %s is invalid", tokens[1])
+			}
+			direction = " " + direction
 		}
 
-		logicalToken := getLogicalToken(token)
-		if logicalToken != "" {
-			result += " " + logicalToken + " "
+		if tokens[0] == rankOrderName {
+			if s.searchValue == "" {
+				return "", fmt.Errorf("%s requires %s in _where", rankOrderName, searchToken)
+			}
+			results = append(results, fmt.Sprintf(`ts_rank(%s."%s", plainto_tsquery(%s, %s))%s`,
+				mainTableAlias, types.SEARCH_VECTOR_COLUMN_NAME, quoteLiteral(s.thing.GetSearchConfig()), s.searchValue, direction))
 			continue
 		}
 
-		result += fmt.Sprintf("$%d", len(s.whereValues)+1)
-		s.whereValues = append(s.whereValues, token)
-	}
+		field, err := s.thing.GetField(tokens[0])
+		if err != nil {
+			return "", err
+		}
 
-	return result, nil
-}
+		if !field.IsAllowed(types.READ, types.GetRole(s.Context)) {
+			return "", fmt.Errorf("field: %s cannot be used in _orderBy", field.Name)
+		}
 
-func getCompareToken(token string) string {
-	compareTokens := []string{"<", ">", "<=", ">=", "="}
-	isMathOperator := slices.Contains(compareTokens, token)
-	if isMathOperator {
-		return token
+		results = append(results, fmt.Sprintf(`%s."%s"%s`, mainTableAlias, field.GetColumnName(), direction))
 	}
-	return ""
-}
 
-func getLogicalToken(token string) string {
-	logicalOperators := []string{"and", "AND", "or", "OR"}
-	isLogicalOrerator := slices.Contains(logicalOperators, token)
-	if isLogicalOrerator {
-		return token
-	}
-	return ""
+	return strings.Join(results, ", "), nil
 }
@@ -1,10 +1,16 @@
 package generators_test
 
 import (
+	"context"
+	"fmt"
 	"json2sql/generators"
 	"json2sql/types"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/exp/maps"
 )
 
 func TestSimpleSelect(t *testing.T) {
@@ -84,3 +90,583 @@ OFFSET 0`
 		t.Fatalf("expected: %s got: %s", "true", whereValues[1])
 	}
 }
+
+func TestSelectAssignedToUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: ownedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"_where": "string = 'a' OR string = 'b'",
+		},
+		Context: types.WithUserId(context.Background(), "user1"),
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "owned_thing" t
+WHERE t."owner_id" = $1 AND (t."string" = $2 OR t."string" = $3)`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	if len(whereValues) != 3 || whereValues[0] != "user1" {
+		t.Fatalf("expected owner as first where value got: %v", whereValues)
+	}
+}
+
+func TestSelectAssignedToUserWithoutUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: ownedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+		},
+	}
+
+	_, err := s.GetSql()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestSelectHiddenField(t *testing.T) {
+	types.Clear()
+	types.Register(securedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: securedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"secret": "",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "field: secret is not allowed to read"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+
+	s = generators.SelectFromTable{
+		ThingName: securedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"secret": "",
+		},
+		SkipForbiddenFields: true,
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "secured_thing" t`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	delete(s.FieldsMap, "string")
+	_, err = s.GetSql()
+
+	expectedError = "no fields to select in thing: securedThing"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectWhereOnHiddenField(t *testing.T) {
+	types.Clear()
+	types.Register(securedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: securedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"_where": "secret = 'test'",
+		},
+		SkipForbiddenFields: true,
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "field: secret cannot be used in _where"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectWhereCoercion(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"integer": "",
+			"_where":  "integer > 5 AND timestamp < 2024-01-02T15:04:05Z AND uuid = 0b8f3a52-6a3e-4c1e-9d57-2a8c5c1f0e11",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."integer" as "integer"
+FROM "typed_thing" t
+WHERE COALESCE(t."integer", 0) > $1 AND t."timestamp" < $2 AND t."uuid" = $3`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	if whereValues[0] != int64(5) {
+		t.Fatalf("expected: %v got: %v", int64(5), whereValues[0])
+	}
+
+	expectedTimestamp := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	timestamp, ok := whereValues[1].(time.Time)
+	if !ok || !timestamp.Equal(expectedTimestamp) {
+		t.Fatalf("expected: %v got: %v", expectedTimestamp, whereValues[1])
+	}
+}
+
+func TestSelectWhereInvalidLiteral(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"integer": "",
+			"_where":  "uuid = 'not a uuid'",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "value: not a uuid is not uuid for field: uuid"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectWhereJsonPath(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"metadata": "",
+			"_where":   "metadata.address.city = 'Oslo'",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."metadata" as "metadata"
+FROM "typed_thing" t
+WHERE (t."metadata" -> $1::text ->> $2::text) = $3`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	if len(whereValues) != 3 || whereValues[0] != "address" || whereValues[1] != "city" || whereValues[2] != "Oslo" {
+		t.Fatalf("expected: [address city Oslo] got: %v", whereValues)
+	}
+}
+
+func TestSelectWhereEnumValue(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"status": "",
+			"_where": "status = archived",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "value: archived is not allowed for field: status"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectWhereArrayOperators(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"tags":   "",
+			"_where": "tags contains 'red,blue' OR scores overlaps 1,2.5",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."tags" as "tags"
+FROM "typed_thing" t
+WHERE t."tags" @> $1 OR t."scores" && $2`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	tags, ok := whereValues[0].(pq.StringArray)
+	if !ok || len(tags) != 2 {
+		t.Fatalf("expected string array got: %v", whereValues[0])
+	}
+
+	scores, ok := whereValues[1].(pq.Float64Array)
+	if !ok || len(scores) != 2 || scores[1] != 2.5 {
+		t.Fatalf("expected float array got: %v", whereValues[1])
+	}
+}
+
+func TestSelectWhereArrayQuotedItems(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"tags":   "",
+			"_where": `tags contains '"red, blue",green'`,
+		},
+	}
+
+	_, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := pq.StringArray{"red, blue", "green"}
+	tags, ok := s.GetWhereValues()[0].(pq.StringArray)
+	if !ok || !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected: %v got: %v", expected, s.GetWhereValues()[0])
+	}
+
+	s.FieldsMap["_where"] = `tags contains '"red,blue'`
+	_, err = s.GetSql()
+	if err == nil {
+		t.Fatal("error expected for unterminated quote")
+	}
+}
+
+func TestSelectWhereArrayOperatorOnScalar(t *testing.T) {
+	types.Clear()
+	types.Register(typedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: typedThing.Name,
+		FieldsMap: map[string]any{
+			"tags":   "",
+			"_where": "integer contains 1",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "operator: contains requires array field"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectSoftDeleted(t *testing.T) {
+	types.Clear()
+	types.Register(softDeletedThing)
+
+	s := generators.SelectFromTable{
+		ThingName: softDeletedThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"_where": "string = test",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "soft_deleted_thing" t
+WHERE t."deleted_at" IS NULL AND (t."string" = $1)`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	s = generators.SelectFromTable{
+		ThingName: softDeletedThing.Name,
+		FieldsMap: map[string]any{
+			"string":       "",
+			"_withDeleted": true,
+		},
+	}
+
+	query, err = s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `SELECT t."string" as "string"
+FROM "soft_deleted_thing" t`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+}
+
+func TestSelectSystemFields(t *testing.T) {
+	types.Clear()
+	thing := versionedThing
+	thing.Timestamps = true
+	types.Register(thing)
+
+	s := generators.SelectFromTable{
+		ThingName: thing.Name,
+		FieldsMap: map[string]any{
+			"string":     "",
+			"_version":   "",
+			"_createdAt": "",
+			"_updatedAt": "",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."created_at" as "_createdAt", t."updated_at" as "_updatedAt", t."version" as "_version", t."string" as "string"
+FROM "versioned_thing" t`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	types.Clear()
+	types.Register(parentThing)
+	s.ThingName = parentThing.Name
+	s.FieldsMap = map[string]any{"string": "", "_version": ""}
+
+	query, err = s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `SELECT t."string" as "string"
+FROM "parent_thing" t`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+}
+
+func TestSelectSearchOrderedByRank(t *testing.T) {
+	types.Clear()
+	types.Register(searchableThing)
+
+	s := generators.SelectFromTable{
+		ThingName: searchableThing.Name,
+		FieldsMap: map[string]any{
+			"title":    "",
+			"_where":   "_search 'red shoes' AND number > 10",
+			"_orderBy": "_rank desc, title",
+		},
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."title" as "title"
+FROM "searchable_thing" t
+WHERE t."search_vector" @@ plainto_tsquery('english', $1) AND COALESCE(t."number", 0) > $2
+ORDER BY ts_rank(t."search_vector", plainto_tsquery('english', $1)) DESC, t."title"`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	whereValues := s.GetWhereValues()
+	if len(whereValues) != 2 || whereValues[0] != "red shoes" {
+		t.Fatalf("expected: [red shoes 10] got: %v", whereValues)
+	}
+}
+
+func TestSelectRankWithoutSearch(t *testing.T) {
+	types.Clear()
+	types.Register(searchableThing)
+
+	s := generators.SelectFromTable{
+		ThingName: searchableThing.Name,
+		FieldsMap: map[string]any{
+			"title":    "",
+			"_orderBy": "_rank",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "_rank requires _search in _where"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectSearchWithoutSearchableFields(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	s := generators.SelectFromTable{
+		ThingName: parentThing.Name,
+		FieldsMap: map[string]any{
+			"string": "",
+			"_where": "_search shoes",
+		},
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "thing: parentThing has no searchable fields"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectSearchWithUnreadableField(t *testing.T) {
+	types.Clear()
+	thing := searchableThing
+	thing.Fields = maps.Clone(searchableThing.Fields)
+	description := thing.Fields["description"]
+	description.Access.Roles = map[string][]types.FieldPermission{"guest": {}}
+	thing.Fields["description"] = description
+	types.Register(thing)
+
+	s := generators.SelectFromTable{
+		ThingName: thing.Name,
+		FieldsMap: map[string]any{
+			"title":  "",
+			"_where": "_search shoes",
+		},
+		Context: types.WithRole(context.Background(), "guest"),
+	}
+
+	_, err := s.GetSql()
+
+	expectedError := "_search cannot be used, field: description is not readable"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestSelectWithRegistry(t *testing.T) {
+	types.Clear()
+	registry := &types.Registry{}
+	registry.Register(parentThing)
+
+	s := generators.SelectFromTable{
+		ThingName: parentThing.Name,
+		FieldsMap: map[string]any{"string": ""},
+		Registry:  registry,
+	}
+
+	query, err := s.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT t."string" as "string"
+FROM "parent_thing" t`
+
+	if query != expected {
+		t.Fatalf("expected: %s, got: %s", expected, query)
+	}
+
+	s.Registry = nil
+	_, err = s.GetSql()
+	if err == nil {
+		t.Fatal("expected error for thing missing from default registry")
+	}
+}
+
+func TestSelectFromCoexistingSchemaVersions(t *testing.T) {
+	v1 := &types.Registry{Schema: "v1"}
+	v1.Register(parentThing)
+	v2 := &types.Registry{Schema: "v2"}
+	v2.Register(parentThing)
+
+	for _, registry := range []*types.Registry{v1, v2} {
+		s := generators.SelectFromTable{
+			ThingName: parentThing.Name,
+			FieldsMap: map[string]any{"string": ""},
+			Registry:  registry,
+		}
+
+		query, err := s.GetSql()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := fmt.Sprintf(`SELECT t."string" as "string"
+FROM "%s"."parent_thing" t`, registry.Schema)
+
+		if query != expected {
+			t.Fatalf("expected: %s, got: %s", expected, query)
+		}
+	}
+}
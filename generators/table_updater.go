@@ -0,0 +1,161 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"json2sql/validators"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type UpdateTable struct {
+	ThingName           string
+	Values              map[string]any
+	Context             context.Context
+	SkipForbiddenFields bool
+	Registry            *types.Registry
+	thing               types.ThingConfig
+}
+
+func (ut *UpdateTable) GetValuesFieldNames() []string {
+	fieldNames := maps.Keys(ut.Values)
+	sort.Strings(fieldNames)
+	return fieldNames
+}
+
+func (ut *UpdateTable) GetSql() (string, error) {
+	var errs []error
+	thing, err := ut.Registry.Get(ut.ThingName)
+	if err != nil {
+		return "", err
+	}
+	ut.thing = thing
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := ut.Values[primaryKey.Name]; !ok {
+		return "", fmt.Errorf("primary key: %s is required to update thing: %s", primaryKey.Name, thing.Name)
+	}
+
+	setString := ""
+	validValues := map[string]any{}
+	systemColumns := getSystemColumnCreateStrings(thing)
+	for _, fieldName := range ut.GetValuesFieldNames() {
+		if fieldName == types.EXPECTED_VERSION_VALUE_NAME && thing.Versioned {
+			continue
+		}
+
+		field, err := thing.GetField(fieldName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if field.Type == types.PRIMARY_KEY {
+			continue
+		}
+
+		if _, ok := systemColumns[field.GetColumnName()]; ok {
+			errs = append(errs, fmt.Errorf("field: %s is managed automatically", field.Name))
+			continue
+		}
+
+		if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			errs = append(errs, fmt.Errorf("relation: %s cannot be updated", field.Name))
+			continue
+		}
+
+		accessible, err := isFieldAccessible(field, types.UPDATE, ut.Context, ut.SkipForbiddenFields)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !accessible {
+			continue
+		}
+		validValues[fieldName] = ut.Values[fieldName]
+
+		setString += fmt.Sprintf(`"%s" = :%s, `, field.GetColumnName(), field.GetValueName())
+	}
+	if setString != "" && thing.Timestamps {
+		setString += fmt.Sprintf(`"%s" = now(), `, types.UPDATED_AT_COLUMN_NAME)
+	}
+	if setString != "" && thing.Versioned {
+		setString += fmt.Sprintf(`"%s" = "%s" + 1, `, types.VERSION_COLUMN_NAME, types.VERSION_COLUMN_NAME)
+	}
+	setString = strings.TrimSuffix(setString, ", ")
+
+	validator := validators.Validator{Partial: true}
+	err = validator.Validate(thing, validValues)
+	if err == nil {
+		err = checkArrayValues(thing, validValues)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if setString == "" {
+		errs = append(errs, fmt.Errorf("no fields to update in thing: %s", thing.Name))
+	}
+
+	whereString, err := ut.getRowWhereString(thing, primaryKey)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if thing.Versioned {
+		if _, ok := ut.Values[types.EXPECTED_VERSION_VALUE_NAME]; !ok {
+			errs = append(errs, fmt.Errorf("%s is required to update thing: %s", types.EXPECTED_VERSION_VALUE_NAME, thing.Name))
+		}
+		whereString += fmt.Sprintf(` AND "%s" = :%s`, types.VERSION_COLUMN_NAME, types.EXPECTED_VERSION_VALUE_NAME)
+	}
+
+	query := fmt.Sprintf(`UPDATE %s
+SET %s
+WHERE %s`, thing.GetTableName(), setString, whereString)
+
+	return query, errors.Join(errs...)
+}
+
+// GetExistsSql selects the updated row regardless of its version, so a
+// versioned update that changed no rows can be told apart from a missing row.
+func (ut *UpdateTable) GetExistsSql() (string, error) {
+	thing, err := ut.Registry.Get(ut.ThingName)
+	if err != nil {
+		return "", err
+	}
+
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return "", err
+	}
+
+	whereString, err := ut.getRowWhereString(thing, primaryKey)
+	return fmt.Sprintf(`SELECT 1 FROM %s
+WHERE %s`, thing.GetTableName(), whereString), err
+}
+
+func (ut *UpdateTable) getRowWhereString(thing types.ThingConfig, primaryKey types.FieldConfig) (string, error) {
+	var err error
+	whereString := fmt.Sprintf(`"%s" = :%s`, primaryKey.GetColumnName(), primaryKey.GetValueName())
+	if thing.Constraints.AssignedToUser {
+		_, err = thing.GetOwnerId(ut.Context)
+		whereString += fmt.Sprintf(` AND "%s" = :%s`, types.OWNER_COLUMN_NAME, types.OWNER_VALUE_NAME)
+	}
+
+	if thing.SoftDelete {
+		whereString += fmt.Sprintf(` AND "%s" IS NULL`, types.DELETED_AT_COLUMN_NAME)
+	}
+	return whereString, err
+}
+
+func (ut *UpdateTable) GetValues() map[string]any {
+	return getNamedValues(ut.Registry, ut.ThingName, ut.Values, ut.Context)
+}
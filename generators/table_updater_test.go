@@ -0,0 +1,211 @@
+package generators_test
+
+import (
+	"context"
+	"json2sql/generators"
+	"json2sql/types"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.UpdateTable{
+		ThingName: parentThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+			"string":     "test",
+			"boolean":    false,
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "parent_thing"
+SET "boolean" = :boolean, "string" = :string
+WHERE "primary_key" = :primaryKey`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestUpdateWithoutPrimaryKey(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := generators.UpdateTable{
+		ThingName: parentThing.Name,
+		Values: map[string]any{
+			"string": "test",
+		},
+	}
+
+	_, err := generator.GetSql()
+
+	expectedError := "primary key: primaryKey is required to update thing: parentThing"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestUpdateAssignedToUser(t *testing.T) {
+	types.Clear()
+	types.Register(ownedThing)
+
+	generator := generators.UpdateTable{
+		ThingName: ownedThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+			"string":     "test",
+		},
+		Context: types.WithUserId(context.Background(), "user1"),
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "owned_thing"
+SET "string" = :string
+WHERE "primary_key" = :primaryKey AND "owner_id" = :_ownerId`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	if generator.GetValues()["_ownerId"] != "user1" {
+		t.Fatal("expected owner id in values")
+	}
+}
+
+func TestUpdateForbiddenField(t *testing.T) {
+	types.Clear()
+	types.Register(securedThing)
+
+	generator := generators.UpdateTable{
+		ThingName: securedThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+			"status":     "published",
+		},
+	}
+
+	_, err := generator.GetSql()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+
+	generator.Context = types.WithRole(context.Background(), "admin")
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "secured_thing"
+SET "status" = :status
+WHERE "primary_key" = :primaryKey`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestUpdateTimestamps(t *testing.T) {
+	types.Clear()
+	types.Register(timestampedThing)
+
+	generator := generators.UpdateTable{
+		ThingName: timestampedThing.Name,
+		Values: map[string]any{
+			"primaryKey": 1,
+			"string":     "test",
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "timestamped_thing"
+SET "string" = :string, "updated_at" = now()
+WHERE "primary_key" = :primaryKey`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
+
+func TestUpdateVersioned(t *testing.T) {
+	types.Clear()
+	types.Register(versionedThing)
+
+	generator := generators.UpdateTable{
+		ThingName: versionedThing.Name,
+		Values: map[string]any{
+			"primaryKey":       1,
+			"string":           "test",
+			"_expectedVersion": 3,
+		},
+	}
+
+	sql, err := generator.GetSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UPDATE "versioned_thing"
+SET "string" = :string, "version" = "version" + 1
+WHERE "primary_key" = :primaryKey AND "version" = :_expectedVersion`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+
+	delete(generator.Values, "_expectedVersion")
+	_, err = generator.GetSql()
+
+	expectedError := "_expectedVersion is required to update thing: versionedThing"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestUpdateExistsSql(t *testing.T) {
+	types.Clear()
+	thing := versionedThing
+	thing.Constraints.AssignedToUser = true
+	types.Register(thing)
+
+	generator := generators.UpdateTable{
+		ThingName: thing.Name,
+		Values: map[string]any{
+			"primaryKey":       1,
+			"string":           "test",
+			"_expectedVersion": 3,
+		},
+		Context: types.WithUserId(context.Background(), 42),
+	}
+
+	sql, err := generator.GetExistsSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT 1 FROM "versioned_thing"
+WHERE "primary_key" = :primaryKey AND "owner_id" = :_ownerId`
+
+	if sql != expected {
+		t.Fatalf("expected: %s got: %s", expected, sql)
+	}
+}
@@ -0,0 +1,318 @@
+package generators
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"json2sql/parsers"
+	"json2sql/types"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const searchToken = "_search"
+
+type whereCompiler struct {
+	thing           types.ThingConfig
+	context         context.Context
+	tableAlias      string
+	inline          bool
+	skipAccessCheck bool
+	values          []any
+	searchValue     string
+}
+
+func (wc *whereCompiler) compile(where string) (string, error) {
+	result := ""
+
+	parser := parsers.Parser{}
+	tokens := parser.Parse(where)
+	if len(tokens) <= 0 {
+		return "", fmt.Errorf("_where is empty")
+	}
+
+	thing := wc.thing
+	var lastField *types.FieldConfig
+	pendingSearch := false
+	for _, token := range tokens {
+		if pendingSearch {
+			valueString, err := wc.addValue(token)
+			if err != nil {
+				return "", err
+			}
+			result += fmt.Sprintf("%s @@ plainto_tsquery(%s, %s)",
+				wc.getColumnString(types.SEARCH_VECTOR_COLUMN_NAME), quoteLiteral(thing.GetSearchConfig()), valueString)
+			wc.searchValue = valueString
+			pendingSearch = false
+			continue
+		}
+
+		if token == searchToken {
+			if len(thing.GetSearchableFields()) == 0 {
+				return "", fmt.Errorf("thing: %s has no searchable fields", thing.Name)
+			}
+			for _, field := range thing.GetSearchableFields() {
+				if !wc.isReadable(field) {
+					return "", fmt.Errorf("%s cannot be used, field: %s is not readable", searchToken, field.Name)
+				}
+			}
+			pendingSearch = true
+			continue
+		}
+
+		whereField, isField := thing.Fields[token]
+		if isField {
+			if !wc.isReadable(whereField) {
+				return "", fmt.Errorf("field: %s cannot be used in _where", token)
+			}
+
+			if whereField.Type.IsNumeric() {
+				result += fmt.Sprintf(`COALESCE(%s, 0) `, wc.getColumnString(whereField.GetColumnName()))
+			} else {
+				result += wc.getColumnString(whereField.GetColumnName()) + " "
+			}
+			lastField = &whereField
+			continue
+		}
+
+		jsonField, jsonPath, isJsonPath := getJsonPath(thing, token)
+		if isJsonPath {
+			if !wc.isReadable(jsonField) {
+				return "", fmt.Errorf("field: %s cannot be used in _where", jsonField.Name)
+			}
+
+			jsonPathString, err := wc.getJsonPathString(jsonField, jsonPath)
+			if err != nil {
+				return "", err
+			}
+			result += jsonPathString + " "
+			lastField = nil
+			continue
+		}
+
+		compareToken := getCompareToken(token)
+		if compareToken != "" {
+			result += compareToken + " "
+			continue
+		}
+
+		arrayToken := getArrayToken(token)
+		if arrayToken != "" {
+			if lastField == nil || lastField.Type != types.ARRAY {
+				return "", fmt.Errorf("operator: %s requires array field", token)
+			}
+			result += arrayToken + " "
+			continue
+		}
+
+		logicalToken := getLogicalToken(token)
+		if logicalToken != "" {
+			result += " " + logicalToken + " "
+			lastField = nil
+			continue
+		}
+
+		var value any = token
+		if lastField != nil {
+			var err error
+			value, err = coerceWhereValue(*lastField, token)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		valueString, err := wc.addValue(value)
+		if err != nil {
+			return "", err
+		}
+		result += valueString
+	}
+
+	if pendingSearch {
+		return "", fmt.Errorf("%s must be followed by search text", searchToken)
+	}
+
+	return result, nil
+}
+
+func (wc *whereCompiler) isReadable(field types.FieldConfig) bool {
+	return wc.skipAccessCheck || field.IsAllowed(types.READ, types.GetRole(wc.context))
+}
+
+func (wc *whereCompiler) getColumnString(columnName string) string {
+	if wc.tableAlias == "" {
+		return fmt.Sprintf(`"%s"`, columnName)
+	}
+	return fmt.Sprintf(`%s."%s"`, wc.tableAlias, columnName)
+}
+
+func (wc *whereCompiler) addValue(value any) (string, error) {
+	if wc.inline {
+		return getLiteralString(value)
+	}
+
+	wc.values = append(wc.values, value)
+	return fmt.Sprintf("$%d", len(wc.values)), nil
+}
+
+func (wc *whereCompiler) getJsonPathString(field types.FieldConfig, keys []string) (string, error) {
+	result := wc.getColumnString(field.GetColumnName())
+	for i, key := range keys {
+		operator := "->"
+		if i == len(keys)-1 {
+			operator = "->>"
+		}
+		keyString, err := wc.addValue(key)
+		if err != nil {
+			return "", err
+		}
+		result += fmt.Sprintf(" %s %s::text", operator, keyString)
+	}
+	return fmt.Sprintf("(%s)", result), nil
+}
+
+func getJsonPath(thing types.ThingConfig, token string) (types.FieldConfig, []string, bool) {
+	fieldName, path, found := strings.Cut(token, ".")
+	if !found {
+		return types.FieldConfig{}, nil, false
+	}
+
+	field, ok := thing.Fields[fieldName]
+	if !ok || field.Type != types.JSON {
+		return types.FieldConfig{}, nil, false
+	}
+
+	keys := strings.Split(path, ".")
+	if slices.Contains(keys, "") {
+		return types.FieldConfig{}, nil, false
+	}
+
+	return field, keys, true
+}
+
+func getLiteralString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return quoteLiteral(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case time.Time:
+		return quoteLiteral(v.Format(time.RFC3339)), nil
+	case driver.Valuer:
+		driverValue, err := v.Value()
+		if err != nil {
+			return "", err
+		}
+		return getLiteralString(driverValue)
+	case []byte:
+		return quoteLiteral(string(v)), nil
+	}
+	return "", fmt.Errorf("value: %v cannot be used as literal", value)
+}
+
+func getCompareToken(token string) string {
+	compareTokens := []string{"<", ">", "<=", ">=", "="}
+	isMathOperator := slices.Contains(compareTokens, token)
+	if isMathOperator {
+		return token
+	}
+	return ""
+}
+
+func getArrayToken(token string) string {
+	arrayOperators := map[string]string{
+		"contains": "@>",
+		"overlaps": "&&",
+	}
+	return arrayOperators[token]
+}
+
+func getLogicalToken(token string) string {
+	logicalOperators := []string{"and", "AND", "or", "OR"}
+	isLogicalOrerator := slices.Contains(logicalOperators, token)
+	if isLogicalOrerator {
+		return token
+	}
+	return ""
+}
+
+// Array items are separated by commas and items containing commas are
+// double quoted, e.g. tags contains '"red, blue",green'.
+func coerceWhereArray(field types.FieldConfig, token string) (driver.Valuer, error) {
+	reader := csv.NewReader(strings.NewReader(token))
+	reader.TrimLeadingSpace = true
+	items, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	itemField := field.GetArrayItemField()
+	values := []any{}
+	for _, item := range items {
+		if itemField.Type == types.NUMBER {
+			number, err := strconv.ParseFloat(item, 64)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, number)
+			continue
+		}
+
+		value, err := coerceWhereValue(itemField, item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return field.GetArrayValue(values)
+}
+
+func coerceWhereValue(field types.FieldConfig, token string) (any, error) {
+	switch field.Type {
+	case types.INTEGER, types.BIGINT:
+		integer, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value: %s is not integer for field: %s", token, field.Name)
+		}
+		return integer, nil
+	case types.DECIMAL:
+		_, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value: %s is not decimal for field: %s", token, field.Name)
+		}
+		return token, nil
+	case types.TIMESTAMP:
+		timestamp, err := time.Parse(time.RFC3339, token)
+		if err != nil {
+			return nil, fmt.Errorf("value: %s is not timestamp for field: %s", token, field.Name)
+		}
+		return timestamp, nil
+	case types.TIME:
+		if !types.IsTime(token) {
+			return nil, fmt.Errorf("value: %s is not time for field: %s", token, field.Name)
+		}
+		return token, nil
+	case types.UUID:
+		if !types.IsUUID(token) {
+			return nil, fmt.Errorf("value: %s is not uuid for field: %s", token, field.Name)
+		}
+		return token, nil
+	case types.ARRAY:
+		arrayValue, err := coerceWhereArray(field, token)
+		if err != nil {
+			return nil, fmt.Errorf("value: %s is not array of %s for field: %s", token, strings.ToLower(string(field.ArrayOf)), field.Name)
+		}
+		return arrayValue, nil
+	case types.ENUM:
+		if !slices.Contains(field.EnumValues, token) {
+			return nil, fmt.Errorf("value: %s is not allowed for field: %s", token, field.Name)
+		}
+		return token, nil
+	}
+	return token, nil
+}
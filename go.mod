@@ -4,10 +4,7 @@ go 1.21.4
 
 require (
 	github.com/iancoleman/strcase v0.3.0
+	github.com/jmoiron/sqlx v1.3.5
+	github.com/lib/pq v1.10.9
 	golang.org/x/exp v0.0.0-20231226003508-02704c960a9b
 )
-
-require (
-	github.com/jmoiron/sqlx v1.3.5 // indirect
-	github.com/lib/pq v1.10.9 // indirect
-)
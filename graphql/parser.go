@@ -0,0 +1,357 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type Operation struct {
+	Type       string
+	Name       string
+	Selections []Selection
+}
+
+type Selection struct {
+	Alias      string
+	Name       string
+	Arguments  map[string]any
+	Selections []Selection
+}
+
+type Variable struct {
+	Name string
+}
+
+type EnumValue string
+
+type parser struct {
+	source []rune
+	index  int
+}
+
+func (s *Selection) GetResultName() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+func Parse(source string) (Operation, error) {
+	p := parser{source: []rune(source)}
+	operation := Operation{Type: "query"}
+
+	p.skipIgnored()
+	if p.peek() != '{' {
+		name, err := p.parseName()
+		if err != nil {
+			return Operation{}, err
+		}
+		if name != "query" && name != "mutation" {
+			return Operation{}, fmt.Errorf("operation: %s is not supported", name)
+		}
+		operation.Type = name
+
+		p.skipIgnored()
+		if p.peek() != '{' && p.peek() != '(' {
+			operation.Name, err = p.parseName()
+			if err != nil {
+				return Operation{}, err
+			}
+		}
+
+		p.skipIgnored()
+		if p.peek() == '(' {
+			err = p.skipVariableDefinitions()
+			if err != nil {
+				return Operation{}, err
+			}
+		}
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return Operation{}, err
+	}
+	operation.Selections = selections
+
+	p.skipIgnored()
+	if p.index < len(p.source) {
+		return Operation{}, fmt.Errorf("only one operation is supported, found: %s", string(p.source[p.index:]))
+	}
+
+	return operation, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	err := p.expect('{')
+	if err != nil {
+		return nil, err
+	}
+
+	selections := []Selection{}
+	for {
+		p.skipIgnored()
+		switch p.peek() {
+		case '}':
+			p.index++
+			return selections, nil
+		case '.', '@':
+			return nil, fmt.Errorf("fragments and directives are not supported")
+		case 0:
+			return nil, fmt.Errorf("unexpected end of query")
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	selection := Selection{Arguments: map[string]any{}}
+	name, err := p.parseName()
+	if err != nil {
+		return selection, err
+	}
+
+	p.skipIgnored()
+	if p.peek() == ':' {
+		p.index++
+		p.skipIgnored()
+		selection.Alias = name
+		name, err = p.parseName()
+		if err != nil {
+			return selection, err
+		}
+	}
+	selection.Name = name
+
+	p.skipIgnored()
+	if p.peek() == '(' {
+		p.index++
+		for {
+			p.skipIgnored()
+			if p.peek() == ')' {
+				p.index++
+				break
+			}
+
+			argumentName, err := p.parseName()
+			if err != nil {
+				return selection, err
+			}
+			p.skipIgnored()
+			err = p.expect(':')
+			if err != nil {
+				return selection, err
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return selection, err
+			}
+			selection.Arguments[argumentName] = value
+		}
+	}
+
+	p.skipIgnored()
+	if p.peek() == '{' {
+		selection.Selections, err = p.parseSelectionSet()
+		if err != nil {
+			return selection, err
+		}
+	}
+
+	return selection, nil
+}
+
+func (p *parser) parseValue() (any, error) {
+	p.skipIgnored()
+	char := p.peek()
+	switch {
+	case char == '$':
+		p.index++
+		name, err := p.parseName()
+		return Variable{Name: name}, err
+	case char == '"':
+		return p.parseString()
+	case char == '[':
+		p.index++
+		values := []any{}
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.index++
+				return values, nil
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	case char == '{':
+		p.index++
+		values := map[string]any{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.index++
+				return values, nil
+			}
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			p.skipIgnored()
+			err = p.expect(':')
+			if err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+		}
+	case char == '-' || unicode.IsDigit(char):
+		return p.parseNumber()
+	}
+
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return EnumValue(name), nil
+}
+
+func (p *parser) parseString() (string, error) {
+	p.index++
+	result := strings.Builder{}
+	for p.index < len(p.source) {
+		char := p.source[p.index]
+		p.index++
+		switch char {
+		case '"':
+			return result.String(), nil
+		case '\\':
+			if p.index >= len(p.source) {
+				return "", fmt.Errorf("unterminated string")
+			}
+			escaped := p.source[p.index]
+			p.index++
+			switch escaped {
+			case 'n':
+				result.WriteRune('\n')
+			case 't':
+				result.WriteRune('\t')
+			case 'r':
+				result.WriteRune('\r')
+			case 'u':
+				if p.index+4 > len(p.source) {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(string(p.source[p.index:p.index+4]), 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				result.WriteRune(rune(code))
+				p.index += 4
+			default:
+				result.WriteRune(escaped)
+			}
+		default:
+			result.WriteRune(char)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *parser) parseNumber() (any, error) {
+	start := p.index
+	p.index++
+	for p.index < len(p.source) && strings.ContainsRune("0123456789.eE+-", p.source[p.index]) {
+		p.index++
+	}
+
+	token := string(p.source[start:p.index])
+	integer, err := strconv.ParseInt(token, 10, 64)
+	if err == nil {
+		return integer, nil
+	}
+	float, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, fmt.Errorf("value: %s is not number", token)
+	}
+	return float, nil
+}
+
+func (p *parser) parseName() (string, error) {
+	start := p.index
+	for p.index < len(p.source) {
+		char := p.source[p.index]
+		if char != '_' && !unicode.IsLetter(char) && !(p.index > start && unicode.IsDigit(char)) {
+			break
+		}
+		p.index++
+	}
+
+	if start == p.index {
+		return "", fmt.Errorf("name expected at: %d", start)
+	}
+	return string(p.source[start:p.index]), nil
+}
+
+func (p *parser) skipVariableDefinitions() error {
+	for p.index < len(p.source) {
+		char := p.source[p.index]
+		p.index++
+		if char == ')' {
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated variable definitions")
+}
+
+func (p *parser) skipIgnored() {
+	for p.index < len(p.source) {
+		char := p.source[p.index]
+		if char == '#' {
+			for p.index < len(p.source) && p.source[p.index] != '\n' {
+				p.index++
+			}
+			continue
+		}
+		if !unicode.IsSpace(char) && char != ',' {
+			return
+		}
+		p.index++
+	}
+}
+
+func (p *parser) peek() rune {
+	if p.index >= len(p.source) {
+		return 0
+	}
+	return p.source[p.index]
+}
+
+func (p *parser) expect(char rune) error {
+	p.skipIgnored()
+	if p.peek() != char {
+		return fmt.Errorf("expected: %c at: %d", char, p.index)
+	}
+	p.index++
+	return nil
+}
@@ -0,0 +1,97 @@
+package graphql_test
+
+import (
+	"json2sql/graphql"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	operation, err := graphql.Parse(`query Parents($where: String) {
+  list: parentThingList(filter: {where: $where}, page: 2, count: 10) {
+    items {
+      string
+      status
+      # comment
+      oneToMany(filter: {orderBy: "primaryKey desc"}) { tags }
+    }
+  }
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := graphql.Operation{
+		Type: "query",
+		Name: "Parents",
+		Selections: []graphql.Selection{
+			{
+				Alias: "list",
+				Name:  "parentThingList",
+				Arguments: map[string]any{
+					"filter": map[string]any{"where": graphql.Variable{Name: "where"}},
+					"page":   int64(2),
+					"count":  int64(10),
+				},
+				Selections: []graphql.Selection{
+					{
+						Name:      "items",
+						Arguments: map[string]any{},
+						Selections: []graphql.Selection{
+							{Name: "string", Arguments: map[string]any{}},
+							{Name: "status", Arguments: map[string]any{}},
+							{
+								Name:      "oneToMany",
+								Arguments: map[string]any{"filter": map[string]any{"orderBy": "primaryKey desc"}},
+								Selections: []graphql.Selection{
+									{Name: "tags", Arguments: map[string]any{}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(operation, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, operation)
+	}
+}
+
+func TestParseMutationValues(t *testing.T) {
+	operation, err := graphql.Parse(`mutation {
+  createParentThing(input: {string: "a \"quoted\" value", status: draft, number: -1.5, tags: ["a", "b"], date: null}) {
+    primaryKey
+  }
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"string": `a "quoted" value`,
+		"status": graphql.EnumValue("draft"),
+		"number": -1.5,
+		"tags":   []any{"a", "b"},
+		"date":   nil,
+	}
+
+	if operation.Type != "mutation" {
+		t.Fatalf("expected: mutation got: %s", operation.Type)
+	}
+	if !reflect.DeepEqual(operation.Selections[0].Arguments["input"], expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, operation.Selections[0].Arguments["input"])
+	}
+}
+
+func TestParseFragment(t *testing.T) {
+	_, err := graphql.Parse(`{ parentThing(id: 1) { ...fields } }`)
+
+	expectedError := "fragments and directives are not supported"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
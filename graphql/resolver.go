@@ -0,0 +1,498 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"json2sql/executors"
+	"json2sql/generators"
+	"json2sql/types"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	typeNameField           = "__typename"
+	expectedVersionArgument = "expectedVersion"
+)
+
+type Resolver struct {
+	Executor *executors.Executor
+}
+
+type loadFunc func(fieldsMap map[string]any) ([]map[string]any, error)
+
+// Execute returns the standard response envelope: "data" holds the result
+// and "errors" the messages of a failed request. A query that cannot be
+// parsed has no "data". The error is returned as well for Go callers.
+func (r *Resolver) Execute(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	operation, err := Parse(query)
+	if err != nil {
+		return map[string]any{"errors": getErrors(err)}, err
+	}
+
+	data, err := r.execute(ctx, operation, variables)
+	if err != nil {
+		return map[string]any{"data": nil, "errors": getErrors(err)}, err
+	}
+	return map[string]any{"data": data}, nil
+}
+
+func getErrors(err error) []map[string]any {
+	return []map[string]any{{"message": err.Error()}}
+}
+
+func (r *Resolver) execute(ctx context.Context, operation Operation, variables map[string]any) (map[string]any, error) {
+	var err error
+	result := map[string]any{}
+	for _, selection := range operation.Selections {
+		selection.Arguments, err = resolveArguments(selection.Arguments, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		if selection.Name == typeNameField {
+			value = strcase.ToCamel(operation.Type)
+		} else if operation.Type == "mutation" {
+			value, err = r.resolveMutation(ctx, selection)
+		} else {
+			value, err = r.resolveQuery(ctx, selection)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", selection.GetResultName(), err)
+		}
+		result[selection.GetResultName()] = value
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveQuery(ctx context.Context, selection Selection) (any, error) {
+	for _, thing := range r.Executor.Registry.GetAll() {
+		queryName := strcase.ToLowerCamel(thing.Name)
+		switch selection.Name {
+		case queryName:
+			key, err := toKey(selection.Arguments["id"])
+			if err != nil {
+				return nil, err
+			}
+			return r.resolveOne(ctx, thing, key, selection.Selections)
+		case queryName + "List":
+			return r.resolveList(ctx, thing, selection)
+		}
+	}
+	return nil, fmt.Errorf("query: %s not found", selection.Name)
+}
+
+func (r *Resolver) resolveMutation(ctx context.Context, selection Selection) (any, error) {
+	for _, thing := range r.Executor.Registry.GetAll() {
+		typeName := GetTypeName(thing)
+		switch selection.Name {
+		case "create" + typeName:
+			values, err := getInputValues(thing, selection.Arguments["input"])
+			if err != nil {
+				return nil, err
+			}
+			key, err := r.Executor.InsertReturningKey(ctx, &generators.InsertIntoTable{
+				ThingName: thing.Name,
+				Values:    values,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return r.resolveOne(ctx, thing, key, selection.Selections)
+		case "update" + typeName:
+			key, err := toKey(selection.Arguments["id"])
+			if err != nil {
+				return nil, err
+			}
+			values, err := getInputValues(thing, selection.Arguments["input"])
+			if err != nil {
+				return nil, err
+			}
+			values[getPrimaryKeyName(thing)] = key
+			if expectedVersion, ok := selection.Arguments[expectedVersionArgument]; ok && expectedVersion != nil && thing.Versioned {
+				version, ok := types.ToInt64(expectedVersion)
+				if !ok {
+					return nil, fmt.Errorf("%s: %v is invalid", expectedVersionArgument, expectedVersion)
+				}
+				values[types.EXPECTED_VERSION_VALUE_NAME] = version
+			}
+			err = r.Executor.Update(ctx, &generators.UpdateTable{
+				ThingName: thing.Name,
+				Values:    values,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return r.resolveOne(ctx, thing, key, selection.Selections)
+		case "delete" + typeName:
+			key, err := toKey(selection.Arguments["id"])
+			if err != nil {
+				return nil, err
+			}
+			err = r.Executor.Delete(ctx, &generators.DeleteFromTable{
+				ThingName: thing.Name,
+				Values:    map[string]any{getPrimaryKeyName(thing): key},
+			})
+			return err == nil, err
+		}
+	}
+	return nil, fmt.Errorf("mutation: %s not found", selection.Name)
+}
+
+func (r *Resolver) resolveOne(ctx context.Context, thing types.ThingConfig, key any, selections []Selection) (any, error) {
+	results, err := r.load(ctx, thing, selections, func(fieldsMap map[string]any) ([]map[string]any, error) {
+		return r.Executor.SelectByIds(ctx, &generators.SelectByIds{
+			ThingName: thing.Name,
+			Ids:       []any{key},
+			FieldsMap: fieldsMap,
+		})
+	})
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+func (r *Resolver) resolveList(ctx context.Context, thing types.ThingConfig, selection Selection) (any, error) {
+	page, err := toUint(selection.Arguments["page"], 1)
+	if err != nil {
+		return nil, err
+	}
+	count, err := toUint(selection.Arguments["count"], 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{}
+	for _, connectionSelection := range selection.Selections {
+		switch connectionSelection.Name {
+		case "items":
+			items, err := r.load(ctx, thing, connectionSelection.Selections, func(fieldsMap map[string]any) ([]map[string]any, error) {
+				err := addFilter(fieldsMap, selection.Arguments["filter"])
+				if err != nil {
+					return nil, err
+				}
+				return r.Executor.Select(ctx, &generators.SelectFromTable{
+					ThingName: thing.Name,
+					FieldsMap: fieldsMap,
+					Page:      page,
+					Count:     count,
+				})
+			})
+			if err != nil {
+				return nil, err
+			}
+			result[connectionSelection.GetResultName()] = items
+		case "page":
+			result[connectionSelection.GetResultName()] = page
+		case "count":
+			result[connectionSelection.GetResultName()] = count
+		case typeNameField:
+			result[connectionSelection.GetResultName()] = GetTypeName(thing) + "Connection"
+		default:
+			return nil, fmt.Errorf("field: %s not in connection", connectionSelection.Name)
+		}
+	}
+	return result, nil
+}
+
+func (r *Resolver) load(ctx context.Context, thing types.ThingConfig, selections []Selection, fetch loadFunc) ([]map[string]any, error) {
+	fieldsMap, err := getFieldsMap(thing, selections)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := fetch(fieldsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	hydratedRows := []map[string]any{}
+	for _, row := range rows {
+		hydratedRow, err := thing.Hydrate(row)
+		if err != nil {
+			return nil, err
+		}
+		hydratedRows = append(hydratedRows, hydratedRow)
+	}
+
+	return r.resolveRows(ctx, thing, selections, hydratedRows)
+}
+
+// Every relation selection is loaded with one query for all rows, so nested
+// selections cost one query per level instead of one per row.
+func (r *Resolver) resolveRows(ctx context.Context, thing types.ThingConfig, selections []Selection, rows []map[string]any) ([]map[string]any, error) {
+	relatedBySelection := map[string]map[string][]map[string]any{}
+	for _, selection := range selections {
+		field, ok := thing.Fields[selection.Name]
+		if !ok || (field.Type != types.THING && field.Type != types.RELATION) {
+			continue
+		}
+
+		related, err := r.loadRelated(ctx, thing, selection, field, rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", selection.GetResultName(), err)
+		}
+		relatedBySelection[selection.GetResultName()] = related
+	}
+
+	primaryKeyName := getPrimaryKeyName(thing)
+	results := []map[string]any{}
+	for _, row := range rows {
+		result := map[string]any{}
+		for _, selection := range selections {
+			resultName := selection.GetResultName()
+			if selection.Name == typeNameField {
+				result[resultName] = GetTypeName(thing)
+				continue
+			}
+
+			field := thing.Fields[selection.Name]
+			related, isRelation := relatedBySelection[resultName]
+			switch {
+			case !isRelation:
+				result[resultName] = row[selection.Name]
+			case isToMany(field):
+				items := related[fmt.Sprint(row[primaryKeyName])]
+				if items == nil {
+					items = []map[string]any{}
+				}
+				result[resultName] = items
+			default:
+				items := related[fmt.Sprint(row[selection.Name])]
+				if len(items) > 0 {
+					result[resultName] = items[0]
+				} else {
+					result[resultName] = nil
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *Resolver) loadRelated(ctx context.Context, thing types.ThingConfig, selection Selection, field types.FieldConfig, rows []map[string]any) (map[string][]map[string]any, error) {
+	otherThingName := field.TypeThingName
+	if field.Type == types.RELATION {
+		otherThingName = field.Relation.OtherThingName
+	}
+	otherThing, err := r.Executor.Registry.Get(otherThingName)
+	if err != nil {
+		return nil, err
+	}
+
+	idsName := selection.Name
+	if isToMany(field) {
+		idsName = getPrimaryKeyName(thing)
+	}
+	ids := []any{}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		id := row[idsName]
+		if id == nil || seen[fmt.Sprint(id)] {
+			continue
+		}
+		seen[fmt.Sprint(id)] = true
+		ids = append(ids, id)
+	}
+
+	related := map[string][]map[string]any{}
+	if len(ids) == 0 {
+		return related, nil
+	}
+
+	relatedRows := []map[string]any{}
+	items, err := r.load(ctx, otherThing, selection.Selections, func(fieldsMap map[string]any) ([]map[string]any, error) {
+		err := addFilter(fieldsMap, selection.Arguments["filter"])
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Type == types.RELATION && field.Relation.Type == types.MANY_TO_MANY {
+			relatedRows, err = r.Executor.SelectRelated(ctx, &generators.SelectRelated{
+				ThingName: thing.Name,
+				FieldName: selection.Name,
+				Ids:       ids,
+				FieldsMap: fieldsMap,
+			})
+			return relatedRows, err
+		}
+
+		selectByIds := generators.SelectByIds{
+			ThingName: otherThing.Name,
+			Ids:       ids,
+			FieldsMap: fieldsMap,
+		}
+		if isToMany(field) {
+			selectByIds.FieldName = field.Relation.OtherFieldName
+		}
+		relatedRows, err = r.Executor.SelectByIds(ctx, &selectByIds)
+		return relatedRows, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		relatedId := fmt.Sprint(relatedRows[i][types.RELATED_ID_ALIAS])
+		related[relatedId] = append(related[relatedId], item)
+	}
+	return related, nil
+}
+
+// The primary key is always selected, so a selection of only __typename
+// still yields a valid query with one row per thing.
+func getFieldsMap(thing types.ThingConfig, selections []Selection) (map[string]any, error) {
+	fieldsMap := map[string]any{}
+	if primaryKeyName := getPrimaryKeyName(thing); primaryKeyName != "" {
+		fieldsMap[primaryKeyName] = ""
+	}
+	for _, selection := range selections {
+		if selection.Name == typeNameField {
+			continue
+		}
+		if _, ok := thing.GetSystemField(selection.Name); ok {
+			fieldsMap[selection.Name] = ""
+			continue
+		}
+
+		field, err := thing.GetField(selection.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if isToMany(field) {
+			fieldsMap[getPrimaryKeyName(thing)] = ""
+			continue
+		}
+		if (field.Type == types.THING || field.Type == types.RELATION) && len(selection.Selections) == 0 {
+			return nil, fmt.Errorf("field: %s requires selection", selection.Name)
+		}
+		fieldsMap[selection.Name] = ""
+	}
+	return fieldsMap, nil
+}
+
+func addFilter(fieldsMap map[string]any, filter any) error {
+	if filter == nil {
+		return nil
+	}
+
+	filterMap, ok := filter.(map[string]any)
+	if !ok {
+		return fmt.Errorf("filter must be object")
+	}
+
+	for name, value := range filterMap {
+		switch name {
+		case "where", "orderBy", "withDeleted":
+			fieldsMap["_"+name] = value
+		default:
+			return fmt.Errorf("filter: %s is not supported", name)
+		}
+	}
+	return nil
+}
+
+func getInputValues(thing types.ThingConfig, input any) (map[string]any, error) {
+	inputMap, ok := input.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("input must be object")
+	}
+
+	values := map[string]any{}
+	for name, value := range inputMap {
+		field, err := thing.GetField(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil && (field.Type == types.THING || field.Type == types.RELATION) {
+			value, err = toKey(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+func resolveArguments(arguments map[string]any, variables map[string]any) (map[string]any, error) {
+	result := map[string]any{}
+	for name, argument := range arguments {
+		value, err := resolveValue(argument, variables)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+func resolveValue(value any, variables map[string]any) (any, error) {
+	switch v := value.(type) {
+	case Variable:
+		return variables[v.Name], nil
+	case EnumValue:
+		return string(v), nil
+	case []any:
+		result := []any{}
+		for _, item := range v {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, resolved)
+		}
+		return result, nil
+	case map[string]any:
+		return resolveArguments(v, variables)
+	default:
+		return value, nil
+	}
+}
+
+func getPrimaryKeyName(thing types.ThingConfig) string {
+	for name, field := range thing.Fields {
+		if field.Type == types.PRIMARY_KEY {
+			return name
+		}
+	}
+	return ""
+}
+
+func isToMany(field types.FieldConfig) bool {
+	return field.Type == types.RELATION &&
+		(field.Relation.Type == types.ONE_TO_MANY || field.Relation.Type == types.MANY_TO_MANY)
+}
+
+func toKey(value any) (int64, error) {
+	if v, ok := value.(string); ok {
+		key, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err == nil {
+			return key, nil
+		}
+	} else if key, ok := types.ToInt64(value); ok {
+		return key, nil
+	}
+	return 0, fmt.Errorf("id: %v is invalid", value)
+}
+
+func toUint(value any, defaultValue uint) (uint, error) {
+	if value == nil {
+		return defaultValue, nil
+	}
+
+	key, err := toKey(value)
+	if err != nil || key <= 0 {
+		return 0, fmt.Errorf("value: %v is not positive integer", value)
+	}
+	return uint(key), nil
+}
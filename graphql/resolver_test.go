@@ -0,0 +1,89 @@
+package graphql_test
+
+import (
+	"context"
+	"json2sql/executors"
+	"json2sql/graphql"
+	"json2sql/types"
+	"reflect"
+	"testing"
+)
+
+func TestResolverErrors(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+	types.Register(childThing)
+
+	resolver := graphql.Resolver{Executor: &executors.Executor{}}
+	tests := map[string]string{
+		`{ missingThing(id: 1) { string } }`:                           "missingThing: query: missingThing not found",
+		`{ parentThing(id: "abc") { string } }`:                        "parentThing: id: abc is invalid",
+		`{ parentThing(id: $id) { string } }`:                          "parentThing: id: <nil> is invalid",
+		`{ parentThing(id: 1) { missing } }`:                           "parentThing: field: missing not in thing: parentThing",
+		`{ childThing(id: 1) { manyToOne } }`:                          "childThing: field: manyToOne requires selection",
+		`{ parentThingList(filter: {limit: 1}) { items { string } } }`: "parentThingList: filter: limit is not supported",
+		`mutation { createParentThing(input: 1) { string } }`:          "createParentThing: input must be object",
+	}
+
+	for query, expectedError := range tests {
+		result, err := resolver.Execute(context.Background(), query, nil)
+		if err == nil {
+			t.Fatalf("error expected for: %s", query)
+		} else if err.Error() != expectedError {
+			t.Fatalf("expected error: %s got: %s", expectedError, err)
+		}
+
+		expected := map[string]any{
+			"data":   nil,
+			"errors": []map[string]any{{"message": expectedError}},
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("expected: %v got: %v", expected, result)
+		}
+	}
+
+	result, err := resolver.Execute(context.Background(), `{ parentThing(id: 1) `, nil)
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if _, ok := result["data"]; ok || len(result["errors"].([]map[string]any)) != 1 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestResolverExpectedVersion(t *testing.T) {
+	types.Clear()
+	thing := parentThing
+	thing.Versioned = true
+	types.Register(thing)
+
+	resolver := graphql.Resolver{Executor: &executors.Executor{}}
+	tests := map[string]string{
+		`mutation { updateParentThing(id: 1, input: {string: "a"}, expectedVersion: "x") { string } }`: "updateParentThing: expectedVersion: x is invalid",
+		`mutation { updateParentThing(id: 1, input: {string: "a"}) { string } }`:                       "updateParentThing: invalid request: _expectedVersion is required to update thing: parentThing",
+	}
+
+	for query, expectedError := range tests {
+		_, err := resolver.Execute(context.Background(), query, nil)
+		if err == nil {
+			t.Fatalf("error expected for: %s", query)
+		} else if err.Error() != expectedError {
+			t.Fatalf("expected error: %s got: %s", expectedError, err)
+		}
+	}
+}
+
+func TestResolverTypeName(t *testing.T) {
+	types.Clear()
+
+	resolver := graphql.Resolver{}
+	result, err := resolver.Execute(context.Background(), `{ __typename }`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{"data": map[string]any{"__typename": "Query"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %v got: %v", expected, result)
+	}
+}
@@ -0,0 +1,258 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+)
+
+var nameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+var scalarTypeNames = map[types.FieldType]string{
+	types.STRING:    "String",
+	types.NUMBER:    "Float",
+	types.BOOLEAN:   "Boolean",
+	types.DATE:      "Date",
+	types.INTEGER:   "Int",
+	types.BIGINT:    "BigInt",
+	types.DECIMAL:   "Decimal",
+	types.TIMESTAMP: "DateTime",
+	types.TIME:      "Time",
+	types.UUID:      "UUID",
+	types.JSON:      "JSON",
+}
+
+var builtInScalars = []string{"String", "Float", "Boolean", "Int", "ID"}
+
+type CreateSchema struct {
+	Registry *types.Registry
+	scalars  map[string]bool
+	enums    []string
+}
+
+func (cs *CreateSchema) GetSdl() (string, error) {
+	cs.scalars = map[string]bool{}
+	cs.enums = []string{}
+
+	things := cs.Registry.GetAll()
+	objects := []string{}
+	queries := []string{}
+	mutations := []string{}
+	var errs []error
+
+	for _, thing := range things {
+		object, err := cs.getThingSdl(thing)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		objects = append(objects, object)
+
+		typeName := GetTypeName(thing)
+		queryName := strcase.ToLowerCamel(thing.Name)
+		updateArguments := fmt.Sprintf("id: ID!, input: %sInput!", typeName)
+		if thing.Versioned {
+			updateArguments += ", " + expectedVersionArgument + ": Int"
+		}
+		queries = append(queries,
+			fmt.Sprintf("  %s(id: ID!): %s", queryName, typeName),
+			fmt.Sprintf("  %sList(filter: %sFilter, page: Int, count: Int): %sConnection!", queryName, typeName, typeName))
+		mutations = append(mutations,
+			fmt.Sprintf("  create%s(input: %sInput!): %s!", typeName, typeName, typeName),
+			fmt.Sprintf("  update%s(%s): %s!", typeName, updateArguments, typeName),
+			fmt.Sprintf("  delete%s(id: ID!): Boolean!", typeName))
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	parts := []string{}
+	scalars := maps.Keys(cs.scalars)
+	sort.Strings(scalars)
+	for _, scalar := range scalars {
+		parts = append(parts, fmt.Sprintf("scalar %s", scalar))
+	}
+	parts = append(parts, cs.enums...)
+	parts = append(parts, objects...)
+	if len(queries) > 0 {
+		parts = append(parts,
+			fmt.Sprintf("type Query {\n%s\n}", strings.Join(queries, "\n")),
+			fmt.Sprintf("type Mutation {\n%s\n}", strings.Join(mutations, "\n")))
+	}
+
+	return strings.Join(parts, "\n\n") + "\n", nil
+}
+
+func GetTypeName(thing types.ThingConfig) string {
+	return strcase.ToCamel(thing.Name)
+}
+
+func (cs *CreateSchema) getThingSdl(thing types.ThingConfig) (string, error) {
+	typeName := GetTypeName(thing)
+	fields := []string{}
+	inputs := []string{}
+	var errs []error
+
+	for _, fieldName := range getFieldNames(thing) {
+		field := thing.Fields[fieldName]
+		if field.Access.Hidden {
+			continue
+		}
+
+		fieldType, err := cs.getFieldType(thing, fieldName, field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("  %s: %s", fieldName, fieldType))
+
+		inputType, err := cs.getInputType(thing, fieldName, field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if inputType != "" {
+			inputs = append(inputs, fmt.Sprintf("  %s: %s", fieldName, inputType))
+		}
+	}
+
+	for _, field := range thing.GetSystemFields() {
+		fieldType, err := cs.getFieldType(thing, field.Name, field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("  %s: %s", field.Name, fieldType))
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	parts := []string{
+		fmt.Sprintf("type %s {\n%s\n}", typeName, strings.Join(fields, "\n")),
+		fmt.Sprintf("type %sConnection {\n  items: [%s!]!\n  page: Int!\n  count: Int!\n}", typeName, typeName),
+		fmt.Sprintf("input %sFilter {\n  where: String\n  orderBy: String\n  withDeleted: Boolean\n}", typeName),
+	}
+	if len(inputs) > 0 {
+		parts = append(parts, fmt.Sprintf("input %sInput {\n%s\n}", typeName, strings.Join(inputs, "\n")))
+	}
+
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func (cs *CreateSchema) getFieldType(thing types.ThingConfig, fieldName string, field types.FieldConfig) (string, error) {
+	nonNull := ""
+	if field.NotNull || field.Type == types.PRIMARY_KEY {
+		nonNull = "!"
+	}
+
+	switch field.Type {
+	case types.PRIMARY_KEY:
+		return "ID!", nil
+	case types.THING:
+		otherThing, err := cs.Registry.Get(field.TypeThingName)
+		if err != nil {
+			return "", err
+		}
+		return GetTypeName(otherThing) + nonNull, nil
+	case types.RELATION:
+		otherThing, err := cs.Registry.Get(field.Relation.OtherThingName)
+		if err != nil {
+			return "", err
+		}
+		if field.Relation.Type == types.MANY_TO_ONE {
+			return GetTypeName(otherThing) + nonNull, nil
+		}
+		return fmt.Sprintf("[%s!]!", GetTypeName(otherThing)), nil
+	}
+
+	scalarType, err := cs.getScalarType(thing, fieldName, field)
+	if err != nil {
+		return "", err
+	}
+	return scalarType + nonNull, nil
+}
+
+func (cs *CreateSchema) getInputType(thing types.ThingConfig, fieldName string, field types.FieldConfig) (string, error) {
+	if !field.IsAllowed(types.INSERT, "") && !field.IsAllowed(types.UPDATE, "") {
+		return "", nil
+	}
+
+	switch field.Type {
+	case types.PRIMARY_KEY:
+		return "", nil
+	case types.THING:
+		return "ID", nil
+	case types.RELATION:
+		if field.Relation.Type == types.MANY_TO_ONE {
+			return "ID", nil
+		}
+		return "", nil
+	}
+
+	return cs.getScalarType(thing, fieldName, field)
+}
+
+func (cs *CreateSchema) getScalarType(thing types.ThingConfig, fieldName string, field types.FieldConfig) (string, error) {
+	switch field.Type {
+	case types.ENUM:
+		return cs.addEnum(thing, fieldName, field), nil
+	case types.ARRAY:
+		itemType, err := cs.getScalarType(thing, fieldName, field.GetArrayItemField())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%s!]", itemType), nil
+	}
+
+	scalarType, ok := scalarTypeNames[field.Type]
+	if !ok {
+		return "", fmt.Errorf("field: %s of type: %s is not supported in graphql", field.Name, field.Type)
+	}
+
+	if !isBuiltInScalar(scalarType) {
+		cs.scalars[scalarType] = true
+	}
+	return scalarType, nil
+}
+
+func (cs *CreateSchema) addEnum(thing types.ThingConfig, fieldName string, field types.FieldConfig) string {
+	for _, value := range field.EnumValues {
+		if !nameRegexp.MatchString(value) {
+			return "String"
+		}
+	}
+
+	enumName := GetTypeName(thing) + strcase.ToCamel(fieldName)
+	enum := fmt.Sprintf("enum %s {\n  %s\n}", enumName, strings.Join(field.EnumValues, "\n  "))
+	for _, existing := range cs.enums {
+		if existing == enum {
+			return enumName
+		}
+	}
+	cs.enums = append(cs.enums, enum)
+	return enumName
+}
+
+func isBuiltInScalar(name string) bool {
+	for _, builtIn := range builtInScalars {
+		if builtIn == name {
+			return true
+		}
+	}
+	return false
+}
+
+func getFieldNames(thing types.ThingConfig) []string {
+	fieldNames := maps.Keys(thing.Fields)
+	sort.Strings(fieldNames)
+	return fieldNames
+}
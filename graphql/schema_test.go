@@ -0,0 +1,207 @@
+package graphql_test
+
+import (
+	"json2sql/graphql"
+	"json2sql/types"
+	"strings"
+	"testing"
+)
+
+var parentThing = types.ThingConfig{
+	Name: "parentThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:    "string",
+			Type:    types.STRING,
+			NotNull: true,
+		},
+		"date": {
+			Name: "date",
+			Type: types.DATE,
+		},
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published"},
+		},
+		"secret": {
+			Name:   "secret",
+			Type:   types.STRING,
+			Access: types.FieldAccess{Hidden: true},
+		},
+		"oneToMany": {
+			Name: "oneToMany",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.ONE_TO_MANY,
+				OtherThingName: "childThing",
+				OtherFieldName: "manyToOne",
+			},
+		},
+	},
+}
+
+var childThing = types.ThingConfig{
+	Name: "childThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"tags": {
+			Name:    "tags",
+			Type:    types.ARRAY,
+			ArrayOf: types.STRING,
+		},
+		"manyToOne": {
+			Name: "manyToOne",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.MANY_TO_ONE,
+				OtherThingName: "parentThing",
+				OtherFieldName: "oneToMany",
+			},
+		},
+	},
+}
+
+func TestCreateSchema(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+	types.Register(childThing)
+
+	generator := graphql.CreateSchema{}
+	sdl, err := generator.GetSdl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `scalar Date
+
+enum ParentThingStatus {
+  draft
+  published
+}
+
+type ChildThing {
+  manyToOne: ParentThing
+  primaryKey: ID!
+  tags: [String!]
+}
+
+type ChildThingConnection {
+  items: [ChildThing!]!
+  page: Int!
+  count: Int!
+}
+
+input ChildThingFilter {
+  where: String
+  orderBy: String
+  withDeleted: Boolean
+}
+
+input ChildThingInput {
+  manyToOne: ID
+  tags: [String!]
+}
+
+type ParentThing {
+  date: Date
+  oneToMany: [ChildThing!]!
+  primaryKey: ID!
+  status: ParentThingStatus
+  string: String!
+}
+
+type ParentThingConnection {
+  items: [ParentThing!]!
+  page: Int!
+  count: Int!
+}
+
+input ParentThingFilter {
+  where: String
+  orderBy: String
+  withDeleted: Boolean
+}
+
+input ParentThingInput {
+  date: Date
+  status: ParentThingStatus
+  string: String
+}
+
+type Query {
+  childThing(id: ID!): ChildThing
+  childThingList(filter: ChildThingFilter, page: Int, count: Int): ChildThingConnection!
+  parentThing(id: ID!): ParentThing
+  parentThingList(filter: ParentThingFilter, page: Int, count: Int): ParentThingConnection!
+}
+
+type Mutation {
+  createChildThing(input: ChildThingInput!): ChildThing!
+  updateChildThing(id: ID!, input: ChildThingInput!): ChildThing!
+  deleteChildThing(id: ID!): Boolean!
+  createParentThing(input: ParentThingInput!): ParentThing!
+  updateParentThing(id: ID!, input: ParentThingInput!): ParentThing!
+  deleteParentThing(id: ID!): Boolean!
+}
+`
+
+	if sdl != expected {
+		t.Fatalf("expected: %s got: %s", expected, sdl)
+	}
+}
+
+func TestCreateSchemaSystemFields(t *testing.T) {
+	types.Clear()
+	thing := childThing
+	thing.Versioned = true
+	thing.Timestamps = true
+	types.Register(thing)
+	types.Register(parentThing)
+
+	generator := graphql.CreateSchema{}
+	sdl, err := generator.GetSdl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `type ChildThing {
+  manyToOne: ParentThing
+  primaryKey: ID!
+  tags: [String!]
+  _createdAt: DateTime!
+  _updatedAt: DateTime!
+  _version: Int!
+}`
+
+	if !strings.Contains(sdl, expected) || !strings.Contains(sdl, "scalar DateTime") {
+		t.Fatalf("expected: %s in: %s", expected, sdl)
+	}
+
+	expected = "  updateChildThing(id: ID!, input: ChildThingInput!, expectedVersion: Int): ChildThing!\n"
+	if !strings.Contains(sdl, expected) {
+		t.Fatalf("expected: %s in: %s", expected, sdl)
+	}
+}
+
+func TestCreateSchemaUnregisteredThing(t *testing.T) {
+	types.Clear()
+	types.Register(childThing)
+
+	generator := graphql.CreateSchema{}
+	_, err := generator.GetSdl()
+
+	expectedError := "thingConfig: parentThing doesn't exists"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
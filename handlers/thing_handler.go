@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"json2sql/executors"
+	"json2sql/generators"
+	"json2sql/types"
+	"json2sql/validators"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const thingsPath = "/things/"
+
+type ThingHandler struct {
+	Executor *executors.Executor
+}
+
+type ErrorResponse struct {
+	Error      string                      `json:"error"`
+	Violations []validators.FieldViolation `json:"violations,omitempty"`
+}
+
+func (h *ThingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, thingsPath)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("path: %s not found", r.URL.Path))
+		return
+	}
+
+	thingName, id, hasId := strings.Cut(strings.Trim(path, "/"), "/")
+	thing, err := h.Executor.Registry.Get(thingName)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !hasId {
+		switch r.Method {
+		case http.MethodGet:
+			h.list(w, r, thing)
+		case http.MethodPost:
+			h.create(w, r, thing)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method: %s is not allowed", r.Method))
+		}
+		return
+	}
+
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("id: %s is invalid", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, thing, key, http.StatusOK)
+	case http.MethodPatch:
+		h.update(w, r, thing, key)
+	case http.MethodDelete:
+		h.delete(w, r, thing, key)
+	default:
+		w.Header().Set("Allow", "GET, PATCH, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method: %s is not allowed", r.Method))
+	}
+}
+
+func (h *ThingHandler) list(w http.ResponseWriter, r *http.Request, thing types.ThingConfig) {
+	query := r.URL.Query()
+	fieldsMap := getFieldsMap(thing, query.Get("fields"))
+	for _, name := range []string{"_where", "_orderBy"} {
+		if query.Has(name) {
+			fieldsMap[name] = query.Get(name)
+		}
+	}
+	if query.Has("_withDeleted") {
+		withDeleted, err := strconv.ParseBool(query.Get("_withDeleted"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("_withDeleted must be boolean"))
+			return
+		}
+		fieldsMap["_withDeleted"] = withDeleted
+	}
+
+	page, err := getUintParam(query.Get("Page"), 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("Page must be positive integer"))
+		return
+	}
+	count, err := getUintParam(query.Get("Count"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("Count must be positive integer"))
+		return
+	}
+
+	rows, err := h.Executor.Select(r.Context(), &generators.SelectFromTable{
+		ThingName:           thing.Name,
+		FieldsMap:           fieldsMap,
+		Page:                page,
+		Count:               count,
+		SkipForbiddenFields: !query.Has("fields"),
+	})
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	results := []map[string]any{}
+	for _, row := range rows {
+		result, err := thing.Hydrate(row)
+		if err != nil {
+			writeExecutorError(w, err)
+			return
+		}
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (h *ThingHandler) get(w http.ResponseWriter, r *http.Request, thing types.ThingConfig, key any, status int) {
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	fieldsMap := getFieldsMap(thing, r.URL.Query().Get("fields"))
+	fieldsMap["_where"] = fmt.Sprintf("%s = %v", primaryKey.Name, key)
+
+	rows, err := h.Executor.Select(r.Context(), &generators.SelectFromTable{
+		ThingName:           thing.Name,
+		FieldsMap:           fieldsMap,
+		SkipForbiddenFields: true,
+	})
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+	if len(rows) == 0 {
+		writeExecutorError(w, executors.ErrNotFound)
+		return
+	}
+
+	result, err := thing.Hydrate(rows[0])
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	writeJSON(w, status, result)
+}
+
+func (h *ThingHandler) create(w http.ResponseWriter, r *http.Request, thing types.ThingConfig) {
+	values, err := readValues(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	key, err := h.Executor.InsertReturningKey(r.Context(), &generators.InsertIntoTable{
+		ThingName: thing.Name,
+		Values:    values,
+	})
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	h.get(w, r, thing, key, http.StatusCreated)
+}
+
+func (h *ThingHandler) update(w http.ResponseWriter, r *http.Request, thing types.ThingConfig, key int64) {
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	values, err := readValues(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	values[primaryKey.Name] = key
+
+	err = h.Executor.Update(r.Context(), &generators.UpdateTable{
+		ThingName: thing.Name,
+		Values:    values,
+	})
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	h.get(w, r, thing, key, http.StatusOK)
+}
+
+func (h *ThingHandler) delete(w http.ResponseWriter, r *http.Request, thing types.ThingConfig, key int64) {
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	err = h.Executor.Delete(r.Context(), &generators.DeleteFromTable{
+		ThingName: thing.Name,
+		Values:    map[string]any{primaryKey.Name: key},
+	})
+	if err != nil {
+		writeExecutorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getFieldsMap(thing types.ThingConfig, fields string) map[string]any {
+	fieldsMap := map[string]any{}
+	if fields != "" {
+		for _, fieldName := range strings.Split(fields, ",") {
+			fieldsMap[strings.TrimSpace(fieldName)] = ""
+		}
+		return fieldsMap
+	}
+
+	for fieldName, field := range thing.Fields {
+		if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			continue
+		}
+		fieldsMap[fieldName] = ""
+	}
+	for _, field := range thing.GetSystemFields() {
+		fieldsMap[field.Name] = ""
+	}
+	return fieldsMap
+}
+
+func getUintParam(value string, defaultValue uint) (uint, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || result == 0 {
+		return 0, fmt.Errorf("value: %s is not positive integer", value)
+	}
+	return uint(result), nil
+}
+
+// readValues decodes numbers as json.Number, so that BIGINT values and ids
+// beyond 2^53 are not rounded through float64.
+func readValues(r *http.Request) (map[string]any, error) {
+	values := map[string]any{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&values)
+	if err != nil {
+		return nil, fmt.Errorf("request body is not json object: %w", err)
+	}
+	return values, nil
+}
+
+func writeExecutorError(w http.ResponseWriter, err error) {
+	var validationError *validators.ValidationError
+	var pqError *pq.Error
+
+	switch {
+	case errors.As(err, &validationError):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error:      "validation failed",
+			Violations: validationError.Violations,
+		})
+	case errors.Is(err, executors.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, executors.ErrConflict):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, executors.ErrInvalidRequest):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.As(err, &pqError) && pqError.Code.Class() == "23":
+		writeError(w, http.StatusConflict, fmt.Errorf("%s", pqError.Message))
+	default:
+		writeError(w, http.StatusInternalServerError, errors.New("internal server error"))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"json2sql/executors"
+	"json2sql/handlers"
+	"json2sql/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var parentThing = types.ThingConfig{
+	Name: "parentThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:    "string",
+			Type:    types.STRING,
+			NotNull: true,
+		},
+		"number": {
+			Name: "number",
+			Type: types.NUMBER,
+		},
+	},
+}
+
+func serve(method string, target string, body string) (*httptest.ResponseRecorder, handlers.ErrorResponse) {
+	types.Clear()
+	types.Register(parentThing)
+
+	handler := handlers.ThingHandler{Executor: &executors.Executor{}}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+	response := handlers.ErrorResponse{}
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestThingHandlerUnknownThing(t *testing.T) {
+	recorder, response := serve(http.MethodGet, "/things/missingThing", "")
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected: %d got: %d", http.StatusNotFound, recorder.Code)
+	}
+	if response.Error == "" {
+		t.Fatal("expected error message")
+	}
+}
+
+func TestThingHandlerMethodNotAllowed(t *testing.T) {
+	recorder, _ := serve(http.MethodPut, "/things/parentThing/1", "")
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected: %d got: %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+	if recorder.Header().Get("Allow") != "GET, PATCH, DELETE" {
+		t.Fatalf("unexpected Allow header: %s", recorder.Header().Get("Allow"))
+	}
+}
+
+func TestThingHandlerInvalidOrderBy(t *testing.T) {
+	recorder, _ := serve(http.MethodGet, "/things/parentThing?_orderBy=missing+desc", "")
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected: %d got: %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestThingHandlerInvalidCount(t *testing.T) {
+	recorder, _ := serve(http.MethodGet, "/things/parentThing?Count=-1", "")
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected: %d got: %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestThingHandlerValidationError(t *testing.T) {
+	recorder, response := serve(http.MethodPost, "/things/parentThing", `{"number": "one"}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected: %d got: %d", http.StatusBadRequest, recorder.Code)
+	}
+	if len(response.Violations) != 2 {
+		t.Fatalf("expected 2 violations got: %v", response.Violations)
+	}
+}
+
+func TestThingHandlerInvalidBody(t *testing.T) {
+	recorder, _ := serve(http.MethodPatch, "/things/parentThing/1", `[1]`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected: %d got: %d", http.StatusBadRequest, recorder.Code)
+	}
+}
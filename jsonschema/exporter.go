@@ -0,0 +1,195 @@
+package jsonschema
+
+import (
+	"fmt"
+	"json2sql/types"
+	"sort"
+
+	"golang.org/x/exp/maps"
+)
+
+const draftUri = "https://json-schema.org/draft/2020-12/schema"
+
+type ExportSchema struct {
+	ThingName string
+	Registry  *types.Registry
+	defs      map[string]any
+}
+
+func (es *ExportSchema) GetSchema() (map[string]any, error) {
+	thing, err := es.Registry.Get(es.ThingName)
+	if err != nil {
+		return nil, err
+	}
+
+	es.defs = map[string]any{}
+	es.defs[thing.Name] = nil
+	schema, err := es.getThingSchema(thing)
+	if err != nil {
+		return nil, err
+	}
+	delete(es.defs, thing.Name)
+
+	schema["$schema"] = draftUri
+	schema["$id"] = thing.Name
+	schema["title"] = thing.Name
+	if len(es.defs) > 0 {
+		schema["$defs"] = es.defs
+	}
+	return schema, nil
+}
+
+func (es *ExportSchema) getThingSchema(thing types.ThingConfig) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	fieldNames := maps.Keys(thing.Fields)
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		field := thing.Fields[fieldName]
+		if field.Access.Hidden {
+			continue
+		}
+
+		fieldSchema, err := es.getFieldSchema(field)
+		if err != nil {
+			return nil, err
+		}
+		properties[fieldName] = fieldSchema
+
+		if field.NotNull || field.Type == types.PRIMARY_KEY {
+			required = append(required, fieldName)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+func (es *ExportSchema) getFieldSchema(field types.FieldConfig) (map[string]any, error) {
+	if field.Type != types.THING && field.Type != types.RELATION {
+		return GetFieldSchema(field)
+	}
+
+	otherThingName := field.TypeThingName
+	if field.Type == types.RELATION {
+		otherThingName = field.Relation.OtherThingName
+	}
+
+	ref, err := es.addDef(otherThingName)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+		return map[string]any{"type": "array", "items": ref}, nil
+	}
+	return ref, nil
+}
+
+func (es *ExportSchema) addDef(otherThingName string) (map[string]any, error) {
+	ref := map[string]any{"$ref": "#/$defs/" + otherThingName}
+	if otherThingName == es.ThingName {
+		ref["$ref"] = "#"
+	}
+
+	if _, ok := es.defs[otherThingName]; ok {
+		return ref, nil
+	}
+
+	otherThing, err := es.Registry.Get(otherThingName)
+	if err != nil {
+		return nil, err
+	}
+
+	es.defs[otherThingName] = nil
+	schema, err := es.getThingSchema(otherThing)
+	if err != nil {
+		return nil, err
+	}
+	schema["title"] = otherThingName
+	es.defs[otherThingName] = schema
+
+	return ref, nil
+}
+
+func GetFieldSchema(field types.FieldConfig) (map[string]any, error) {
+	schema := map[string]any{}
+
+	switch field.Type {
+	case types.PRIMARY_KEY:
+		schema["type"] = "integer"
+		schema["readOnly"] = true
+	case types.STRING:
+		schema["type"] = "string"
+		if field.MaxLength > 0 {
+			schema["maxLength"] = field.MaxLength
+		}
+		if field.Pattern != "" {
+			schema["pattern"] = field.Pattern
+		}
+	case types.NUMBER:
+		schema["type"] = "number"
+	case types.INTEGER:
+		schema["type"] = "integer"
+		schema["format"] = "int32"
+	case types.BIGINT:
+		schema["type"] = "integer"
+		schema["format"] = "int64"
+	case types.DECIMAL:
+		schema["type"] = []string{"string", "number"}
+		schema["format"] = "decimal"
+	case types.BOOLEAN:
+		schema["type"] = "boolean"
+	case types.DATE:
+		schema["type"] = "string"
+		schema["format"] = "date"
+	case types.TIMESTAMP:
+		schema["type"] = "string"
+		schema["format"] = "date-time"
+	case types.TIME:
+		schema["type"] = "string"
+		schema["format"] = "time"
+	case types.UUID:
+		schema["type"] = "string"
+		schema["format"] = "uuid"
+	case types.JSON:
+	case types.ENUM:
+		schema["type"] = "string"
+		schema["enum"] = field.EnumValues
+	case types.ARRAY:
+		items, err := GetFieldSchema(field.GetArrayItemField())
+		if err != nil {
+			return nil, err
+		}
+		schema["type"] = "array"
+		schema["items"] = items
+	case types.THING, types.RELATION:
+		otherThingName := field.TypeThingName
+		if field.Type == types.RELATION {
+			otherThingName = field.Relation.OtherThingName
+		}
+		schema["type"] = "integer"
+		schema["description"] = fmt.Sprintf("Id of %s", otherThingName)
+	default:
+		return nil, fmt.Errorf("field: %s of type: %s is not supported", field.Name, field.Type)
+	}
+
+	if field.Type.IsNumeric() {
+		if field.Min != nil {
+			schema["minimum"] = *field.Min
+		}
+		if field.Max != nil {
+			schema["maximum"] = *field.Max
+		}
+	}
+
+	return schema, nil
+}
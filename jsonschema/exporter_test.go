@@ -0,0 +1,210 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"json2sql/jsonschema"
+	"json2sql/types"
+	"testing"
+)
+
+var maxNumber = 100.0
+
+var parentThing = types.ThingConfig{
+	Name: "parentThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:      "string",
+			Type:      types.STRING,
+			NotNull:   true,
+			MaxLength: 20,
+		},
+		"number": {
+			Name: "number",
+			Type: types.NUMBER,
+			Max:  &maxNumber,
+		},
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published"},
+		},
+		"thing": {
+			Name:          "thing",
+			Type:          types.THING,
+			TypeThingName: "otherThing",
+		},
+		"oneToMany": {
+			Name: "oneToMany",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.ONE_TO_MANY,
+				OtherThingName: "childThing",
+				OtherFieldName: "manyToOne",
+			},
+		},
+	},
+}
+
+var childThing = types.ThingConfig{
+	Name: "childThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"tags": {
+			Name:    "tags",
+			Type:    types.ARRAY,
+			ArrayOf: types.STRING,
+		},
+		"manyToOne": {
+			Name: "manyToOne",
+			Type: types.RELATION,
+			Relation: types.ThingRelation{
+				Type:           types.MANY_TO_ONE,
+				OtherThingName: "parentThing",
+				OtherFieldName: "oneToMany",
+			},
+		},
+	},
+}
+
+var otherThing = types.ThingConfig{
+	Name: "otherThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"uuid": {
+			Name: "uuid",
+			Type: types.UUID,
+		},
+	},
+}
+
+const expectedSchema = `{
+  "$defs": {
+    "childThing": {
+      "additionalProperties": false,
+      "properties": {
+        "manyToOne": {
+          "$ref": "#"
+        },
+        "primaryKey": {
+          "readOnly": true,
+          "type": "integer"
+        },
+        "tags": {
+          "items": {
+            "type": "string"
+          },
+          "type": "array"
+        }
+      },
+      "required": [
+        "primaryKey"
+      ],
+      "title": "childThing",
+      "type": "object"
+    },
+    "otherThing": {
+      "additionalProperties": false,
+      "properties": {
+        "primaryKey": {
+          "readOnly": true,
+          "type": "integer"
+        },
+        "uuid": {
+          "format": "uuid",
+          "type": "string"
+        }
+      },
+      "required": [
+        "primaryKey"
+      ],
+      "title": "otherThing",
+      "type": "object"
+    }
+  },
+  "$id": "parentThing",
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "additionalProperties": false,
+  "properties": {
+    "number": {
+      "maximum": 100,
+      "type": "number"
+    },
+    "oneToMany": {
+      "items": {
+        "$ref": "#/$defs/childThing"
+      },
+      "type": "array"
+    },
+    "primaryKey": {
+      "readOnly": true,
+      "type": "integer"
+    },
+    "status": {
+      "enum": [
+        "draft",
+        "published"
+      ],
+      "type": "string"
+    },
+    "string": {
+      "maxLength": 20,
+      "type": "string"
+    },
+    "thing": {
+      "$ref": "#/$defs/otherThing"
+    }
+  },
+  "required": [
+    "primaryKey",
+    "string"
+  ],
+  "title": "parentThing",
+  "type": "object"
+}`
+
+func TestExportSchema(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+	types.Register(childThing)
+	types.Register(otherThing)
+
+	generator := jsonschema.ExportSchema{ThingName: parentThing.Name}
+	schema, err := generator.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != expectedSchema {
+		t.Fatalf("expected: %s got: %s", expectedSchema, data)
+	}
+}
+
+func TestExportSchemaUnregisteredThing(t *testing.T) {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := jsonschema.ExportSchema{ThingName: parentThing.Name}
+	_, err := generator.GetSchema()
+
+	expectedError := "thingConfig: childThing doesn't exists"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
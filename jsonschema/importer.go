@@ -0,0 +1,278 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"json2sql/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+const defaultPrimaryKeyName = "id"
+
+type ImportSchema struct {
+	Schema []byte
+}
+
+func (is *ImportSchema) GetThingConfigs() ([]types.ThingConfig, error) {
+	root := map[string]any{}
+	err := json.Unmarshal(is.Schema, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	rootName, _ := root["title"].(string)
+	if rootName == "" {
+		rootName, _ = root["$id"].(string)
+	}
+	if rootName == "" {
+		return nil, fmt.Errorf("schema must have title or $id")
+	}
+
+	schemas := map[string]map[string]any{rootName: root}
+	defs, _ := root["$defs"].(map[string]any)
+	for name, def := range defs {
+		defSchema, ok := def.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$defs: %s must be object", name)
+		}
+		schemas[name] = defSchema
+	}
+
+	things := []types.ThingConfig{}
+	var errs []error
+	names := maps.Keys(schemas)
+	sort.Strings(names)
+	for _, name := range names {
+		thing, err := getThingConfig(name, rootName, schemas[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		things = append(things, thing)
+	}
+
+	linkRelations(things)
+	return things, errors.Join(errs...)
+}
+
+// An array of references whose target refers back with exactly one field is
+// a ONE_TO_MANY relation; any other array of references is MANY_TO_MANY.
+func linkRelations(things []types.ThingConfig) {
+	thingsByName := map[string]types.ThingConfig{}
+	for _, thing := range things {
+		thingsByName[thing.Name] = thing
+	}
+
+	for _, thing := range things {
+		for fieldName, field := range thing.Fields {
+			if field.Type != types.RELATION || field.Relation.Type != types.MANY_TO_MANY {
+				continue
+			}
+
+			otherThing, ok := thingsByName[field.Relation.OtherThingName]
+			if !ok {
+				continue
+			}
+
+			backFieldNames := []string{}
+			for otherFieldName, otherField := range otherThing.Fields {
+				if otherField.Type == types.THING && otherField.TypeThingName == thing.Name {
+					backFieldNames = append(backFieldNames, otherFieldName)
+				}
+			}
+			if len(backFieldNames) != 1 {
+				continue
+			}
+
+			backField := otherThing.Fields[backFieldNames[0]]
+			backField.Type = types.RELATION
+			backField.TypeThingName = ""
+			backField.Relation = types.ThingRelation{
+				Type:           types.MANY_TO_ONE,
+				OtherThingName: thing.Name,
+				OtherFieldName: fieldName,
+			}
+			otherThing.Fields[backFieldNames[0]] = backField
+
+			field.Relation.Type = types.ONE_TO_MANY
+			field.Relation.OtherFieldName = backFieldNames[0]
+			thing.Fields[fieldName] = field
+		}
+	}
+}
+
+func getThingConfig(name string, rootName string, schema map[string]any) (types.ThingConfig, error) {
+	if getType(schema) != "object" {
+		return types.ThingConfig{}, fmt.Errorf("schema: %s must be object", name)
+	}
+
+	thing := types.ThingConfig{Name: name, Fields: map[string]types.FieldConfig{}}
+	properties, _ := schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	requiredNames, _ := schema["required"].([]any)
+	for _, requiredName := range requiredNames {
+		if s, ok := requiredName.(string); ok {
+			required[s] = true
+		}
+	}
+
+	var errs []error
+	hasPrimaryKey := false
+	for fieldName, property := range properties {
+		propertySchema, ok := property.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("property: %s of schema: %s must be object", fieldName, name))
+			continue
+		}
+
+		field, err := getFieldConfig(fieldName, rootName, propertySchema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("property: %s of schema: %s %w", fieldName, name, err))
+			continue
+		}
+		if field.Type == types.PRIMARY_KEY {
+			hasPrimaryKey = true
+		} else {
+			field.NotNull = required[fieldName]
+		}
+		thing.Fields[fieldName] = field
+	}
+
+	if !hasPrimaryKey {
+		if _, ok := thing.Fields[defaultPrimaryKeyName]; ok {
+			errs = append(errs, fmt.Errorf("schema: %s has no read only integer primary key", name))
+		}
+		thing.Fields[defaultPrimaryKeyName] = types.FieldConfig{Name: defaultPrimaryKeyName, Type: types.PRIMARY_KEY}
+	}
+
+	return thing, errors.Join(errs...)
+}
+
+func getFieldConfig(fieldName string, rootName string, schema map[string]any) (types.FieldConfig, error) {
+	field := types.FieldConfig{Name: fieldName}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		field.Type = types.THING
+		field.TypeThingName = getRefName(ref, rootName)
+		return field, nil
+	}
+
+	format, _ := schema["format"].(string)
+	switch getType(schema) {
+	case "integer":
+		if readOnly, _ := schema["readOnly"].(bool); readOnly {
+			field.Type = types.PRIMARY_KEY
+			return field, nil
+		}
+		field.Type = types.INTEGER
+		if format == "int64" {
+			field.Type = types.BIGINT
+		}
+	case "number":
+		field.Type = types.NUMBER
+		if format == "decimal" {
+			field.Type = types.DECIMAL
+		}
+	case "boolean":
+		field.Type = types.BOOLEAN
+	case "string":
+		field.Type = getStringType(format)
+		if field.Type == types.DECIMAL {
+			break
+		}
+		if enumValues, ok := schema["enum"].([]any); ok {
+			field.Type = types.ENUM
+			for _, enumValue := range enumValues {
+				field.EnumValues = append(field.EnumValues, fmt.Sprint(enumValue))
+			}
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok {
+			field.MaxLength = int(maxLength)
+		}
+		field.Pattern, _ = schema["pattern"].(string)
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		itemField, err := getFieldConfig(fieldName, rootName, items)
+		if err == nil && itemField.Type == types.THING {
+			field.Type = types.RELATION
+			field.Relation = types.ThingRelation{
+				Type:           types.MANY_TO_MANY,
+				OtherThingName: itemField.TypeThingName,
+			}
+			return field, nil
+		}
+		if err != nil || !isArrayItemType(itemField.Type) {
+			field.Type = types.JSON
+			return field, nil
+		}
+		field.Type = types.ARRAY
+		field.ArrayOf = itemField.Type
+	default:
+		field.Type = types.JSON
+	}
+
+	if minimum, ok := schema["minimum"].(float64); ok {
+		field.Min = &minimum
+	}
+	if maximum, ok := schema["maximum"].(float64); ok {
+		field.Max = &maximum
+	}
+
+	return field, nil
+}
+
+func getType(schema map[string]any) string {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return schemaType
+	case []any:
+		for _, t := range schemaType {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func getStringType(format string) types.FieldType {
+	switch format {
+	case "date":
+		return types.DATE
+	case "date-time":
+		return types.TIMESTAMP
+	case "time":
+		return types.TIME
+	case "uuid":
+		return types.UUID
+	case "decimal":
+		return types.DECIMAL
+	default:
+		return types.STRING
+	}
+}
+
+func getRefName(ref string, rootName string) string {
+	if ref == "#" {
+		return rootName
+	}
+	return strings.TrimPrefix(ref, "#/$defs/")
+}
+
+// isArrayItemType reports whether items of fieldType can be stored in an
+// ARRAY field. Other arrays are imported as JSON.
+func isArrayItemType(fieldType types.FieldType) bool {
+	switch fieldType {
+	case types.STRING, types.NUMBER, types.INTEGER, types.BIGINT:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,67 @@
+package jsonschema_test
+
+import (
+	"json2sql/jsonschema"
+	"json2sql/types"
+	"reflect"
+	"testing"
+)
+
+func TestImportSchema(t *testing.T) {
+	importer := jsonschema.ImportSchema{Schema: []byte(expectedSchema)}
+	things, err := importer.GetThingConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.ThingConfig{childThing, otherThing, parentThing}
+	if !reflect.DeepEqual(things, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, things)
+	}
+}
+
+func TestImportSchemaWithoutPrimaryKey(t *testing.T) {
+	importer := jsonschema.ImportSchema{Schema: []byte(`{
+  "title": "person",
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {"type": ["string", "null"]},
+    "born": {"type": "string", "format": "date"},
+    "address": {"type": "object", "properties": {"city": {"type": "string"}}},
+    "scores": {"type": "array", "items": {"type": "integer", "format": "int64"}},
+    "flags": {"type": "array", "items": {"type": "boolean"}}
+  }
+}`)}
+	things, err := importer.GetThingConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.ThingConfig{{
+		Name: "person",
+		Fields: map[string]types.FieldConfig{
+			"id":      {Name: "id", Type: types.PRIMARY_KEY},
+			"name":    {Name: "name", Type: types.STRING, NotNull: true},
+			"born":    {Name: "born", Type: types.DATE},
+			"address": {Name: "address", Type: types.JSON},
+			"scores":  {Name: "scores", Type: types.ARRAY, ArrayOf: types.BIGINT},
+			"flags":   {Name: "flags", Type: types.JSON},
+		},
+	}}
+	if !reflect.DeepEqual(things, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, things)
+	}
+}
+
+func TestImportSchemaWithoutTitle(t *testing.T) {
+	importer := jsonschema.ImportSchema{Schema: []byte(`{"type": "object"}`)}
+	_, err := importer.GetThingConfigs()
+
+	expectedError := "schema must have title or $id"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
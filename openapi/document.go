@@ -0,0 +1,294 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"json2sql/jsonschema"
+	"json2sql/types"
+	"sort"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/exp/maps"
+)
+
+const openApiVersion = "3.1.0"
+
+const whereDescription = "Filter expression of space separated tokens. Compare fields with =, <, <=, > and >=, " +
+	"combine comparisons with and/or, quote values containing spaces with single quotes, " +
+	"use field.path for JSON fields, contains/overlaps with comma separated items for array fields " +
+	"(double quote items containing commas) and _search 'text' for full-text search. " +
+	"Example: string = 'some value' and number > 1"
+
+type CreateDocument struct {
+	Title    string
+	Version  string
+	Registry *types.Registry
+}
+
+func (cd *CreateDocument) GetDocument() (map[string]any, error) {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type":     "object",
+			"required": []string{"error"},
+			"properties": map[string]any{
+				"error": map[string]any{"type": "string"},
+				"violations": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":     "object",
+						"required": []string{"field", "message"},
+						"properties": map[string]any{
+							"field":   map[string]any{"type": "string"},
+							"message": map[string]any{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+	paths := map[string]any{}
+	var errs []error
+
+	for _, thing := range cd.Registry.GetAll() {
+		typeName := strcase.ToCamel(thing.Name)
+
+		thingSchema, err := getThingSchema(thing, types.READ)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		insertSchema, err := getThingSchema(thing, types.INSERT)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		updateSchema, err := getThingSchema(thing, types.UPDATE)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		schemas[typeName] = thingSchema
+		schemas[typeName+"Insert"] = insertSchema
+		schemas[typeName+"Update"] = updateSchema
+
+		itemPath, err := getItemPath(thing, typeName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		paths[fmt.Sprintf("/things/%s", thing.Name)] = getCollectionPath(thing, typeName)
+		paths[fmt.Sprintf("/things/%s/{id}", thing.Name)] = itemPath
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return map[string]any{
+		"openapi": openApiVersion,
+		"info": map[string]any{
+			"title":   cd.Title,
+			"version": cd.Version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"responses": map[string]any{
+				"BadRequest": getErrorResponse("The request is invalid"),
+				"NotFound":   getErrorResponse("The thing was not found"),
+				"Conflict":   getErrorResponse("The thing was modified concurrently or violates a constraint"),
+			},
+		},
+	}, nil
+}
+
+func getThingSchema(thing types.ThingConfig, permission types.FieldPermission) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+	var errs []error
+
+	fieldNames := maps.Keys(thing.Fields)
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		field := thing.Fields[fieldName]
+		if !field.IsAllowed(permission, "") {
+			continue
+		}
+		if field.Type == types.PRIMARY_KEY && permission != types.READ {
+			continue
+		}
+		if field.Type == types.RELATION && field.Relation.Type != types.MANY_TO_ONE {
+			continue
+		}
+
+		fieldSchema, err := jsonschema.GetFieldSchema(field)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		properties[fieldName] = fieldSchema
+
+		isRequired := field.Type == types.PRIMARY_KEY || field.NotNull
+		if permission == types.INSERT {
+			isRequired = field.NotNull && field.Default == ""
+		}
+		if permission != types.UPDATE && isRequired {
+			required = append(required, fieldName)
+		}
+	}
+
+	if permission == types.READ {
+		for _, field := range thing.GetSystemFields() {
+			fieldSchema, err := jsonschema.GetFieldSchema(field)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fieldSchema["readOnly"] = true
+			properties[field.Name] = fieldSchema
+			required = append(required, field.Name)
+		}
+	}
+
+	if permission == types.UPDATE && thing.Versioned {
+		properties[types.EXPECTED_VERSION_VALUE_NAME] = map[string]any{
+			"type":        "integer",
+			"description": "Version the update is based on; the update fails with 409 when it changed",
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, errors.Join(errs...)
+}
+
+func getCollectionPath(thing types.ThingConfig, typeName string) map[string]any {
+	parameters := []any{
+		getQueryParameter("fields", "Comma separated field names to return, all readable fields by default", map[string]any{"type": "string"}),
+		getQueryParameter("_where", whereDescription, map[string]any{"type": "string"}),
+		getQueryParameter("_orderBy", "Comma separated fields with optional asc or desc, _rank orders by search relevance",
+			map[string]any{"type": "string"}),
+		getQueryParameter("Page", "Page number starting at 1, used with Count", map[string]any{"type": "integer", "minimum": 1}),
+		getQueryParameter("Count", "Number of things per page", map[string]any{"type": "integer", "minimum": 1}),
+	}
+	if thing.SoftDelete {
+		parameters = append(parameters,
+			getQueryParameter("_withDeleted", "Include soft deleted things", map[string]any{"type": "boolean"}))
+	}
+
+	return map[string]any{
+		"get": map[string]any{
+			"operationId": fmt.Sprintf("list%s", typeName),
+			"parameters":  parameters,
+			"responses": map[string]any{
+				"200": getJSONResponse("List of things", map[string]any{
+					"type":  "array",
+					"items": getRef(typeName),
+				}),
+				"400": getResponseRef("BadRequest"),
+			},
+		},
+		"post": map[string]any{
+			"operationId": fmt.Sprintf("create%s", typeName),
+			"requestBody": getRequestBody(typeName + "Insert"),
+			"responses": map[string]any{
+				"201": getJSONResponse("Created thing", getRef(typeName)),
+				"400": getResponseRef("BadRequest"),
+				"409": getResponseRef("Conflict"),
+			},
+		},
+	}
+}
+
+func getItemPath(thing types.ThingConfig, typeName string) (map[string]any, error) {
+	primaryKey, err := thing.GetPrimaryKey()
+	if err != nil {
+		return nil, err
+	}
+	idSchema, err := jsonschema.GetFieldSchema(primaryKey)
+	if err != nil {
+		return nil, err
+	}
+	delete(idSchema, "readOnly")
+
+	return map[string]any{
+		"parameters": []any{
+			map[string]any{
+				"name":     "id",
+				"in":       "path",
+				"required": true,
+				"schema":   idSchema,
+			},
+		},
+		"get": map[string]any{
+			"operationId": fmt.Sprintf("get%s", typeName),
+			"responses": map[string]any{
+				"200": getJSONResponse("Thing", getRef(typeName)),
+				"404": getResponseRef("NotFound"),
+			},
+		},
+		"patch": map[string]any{
+			"operationId": fmt.Sprintf("update%s", typeName),
+			"requestBody": getRequestBody(typeName + "Update"),
+			"responses": map[string]any{
+				"200": getJSONResponse("Updated thing", getRef(typeName)),
+				"400": getResponseRef("BadRequest"),
+				"404": getResponseRef("NotFound"),
+				"409": getResponseRef("Conflict"),
+			},
+		},
+		"delete": map[string]any{
+			"operationId": fmt.Sprintf("delete%s", typeName),
+			"responses": map[string]any{
+				"204": map[string]any{"description": "Deleted"},
+				"404": getResponseRef("NotFound"),
+			},
+		},
+	}, nil
+}
+
+func getQueryParameter(name string, description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      schema,
+	}
+}
+
+func getRequestBody(schemaName string) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": getRef(schemaName)},
+		},
+	}
+}
+
+func getJSONResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func getErrorResponse(description string) map[string]any {
+	return getJSONResponse(description, getRef("Error"))
+}
+
+func getRef(schemaName string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + schemaName}
+}
+
+func getResponseRef(responseName string) map[string]any {
+	return map[string]any{"$ref": "#/components/responses/" + responseName}
+}
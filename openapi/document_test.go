@@ -0,0 +1,165 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"json2sql/openapi"
+	"json2sql/types"
+	"reflect"
+	"testing"
+)
+
+var minNumber = 1.0
+
+var parentThing = types.ThingConfig{
+	Name:       "parentThing",
+	SoftDelete: true,
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:      "string",
+			Type:      types.STRING,
+			NotNull:   true,
+			MaxLength: 10,
+		},
+		"number": {
+			Name:    "number",
+			Type:    types.INTEGER,
+			NotNull: true,
+			Default: "1",
+			Min:     &minNumber,
+		},
+		"status": {
+			Name:       "status",
+			Type:       types.ENUM,
+			EnumValues: []string{"draft", "published"},
+		},
+		"tags": {
+			Name:    "tags",
+			Type:    types.ARRAY,
+			ArrayOf: types.STRING,
+		},
+		"secret": {
+			Name:   "secret",
+			Type:   types.STRING,
+			Access: types.FieldAccess{Hidden: true},
+		},
+	},
+}
+
+func getDocument(t *testing.T) map[string]any {
+	types.Clear()
+	types.Register(parentThing)
+
+	generator := openapi.CreateDocument{Title: "Things", Version: "1.0.0"}
+	document, err := generator.GetDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := map[string]any{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestCreateDocumentSchemas(t *testing.T) {
+	document := getDocument(t)
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+
+	expected := map[string]any{
+		"type":     "object",
+		"required": []any{"number", "primaryKey", "string"},
+		"properties": map[string]any{
+			"primaryKey": map[string]any{"type": "integer", "readOnly": true},
+			"string":     map[string]any{"type": "string", "maxLength": 10.0},
+			"number":     map[string]any{"type": "integer", "format": "int32", "minimum": 1.0},
+			"status":     map[string]any{"type": "string", "enum": []any{"draft", "published"}},
+			"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}
+	if !reflect.DeepEqual(schemas["ParentThing"], expected) {
+		t.Fatalf("expected: %v got: %v", expected, schemas["ParentThing"])
+	}
+
+	insertRequired := schemas["ParentThingInsert"].(map[string]any)["required"]
+	if !reflect.DeepEqual(insertRequired, []any{"string"}) {
+		t.Fatalf("expected: [string] got: %v", insertRequired)
+	}
+
+	if _, ok := schemas["ParentThingUpdate"].(map[string]any)["required"]; ok {
+		t.Fatal("update schema must not have required fields")
+	}
+}
+
+func TestCreateDocumentPaths(t *testing.T) {
+	document := getDocument(t)
+	if document["openapi"] != "3.1.0" {
+		t.Fatalf("expected: 3.1.0 got: %v", document["openapi"])
+	}
+
+	paths := document["paths"].(map[string]any)
+	collection := paths["/things/parentThing"].(map[string]any)
+	parameters := collection["get"].(map[string]any)["parameters"].([]any)
+
+	names := []string{}
+	for _, parameter := range parameters {
+		names = append(names, parameter.(map[string]any)["name"].(string))
+	}
+	expected := []string{"fields", "_where", "_orderBy", "Page", "Count", "_withDeleted"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected: %v got: %v", expected, names)
+	}
+
+	item := paths["/things/parentThing/{id}"].(map[string]any)
+	for _, method := range []string{"get", "patch", "delete"} {
+		if _, ok := item[method]; !ok {
+			t.Fatalf("expected method: %s", method)
+		}
+	}
+
+	idSchema := item["parameters"].([]any)[0].(map[string]any)["schema"]
+	if !reflect.DeepEqual(idSchema, map[string]any{"type": "integer"}) {
+		t.Fatalf("unexpected id schema: %v", idSchema)
+	}
+}
+
+func TestCreateDocumentSystemFields(t *testing.T) {
+	types.Clear()
+	thing := parentThing
+	thing.Versioned = true
+	thing.Timestamps = true
+	types.Register(thing)
+
+	generator := openapi.CreateDocument{Title: "Things", Version: "1.0.0"}
+	document, err := generator.GetDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	schema := schemas["ParentThing"].(map[string]any)
+	properties := schema["properties"].(map[string]any)
+
+	expected := map[string]any{"type": "integer", "format": "int32", "readOnly": true}
+	if !reflect.DeepEqual(properties["_version"], expected) {
+		t.Fatalf("expected: %v got: %v", expected, properties["_version"])
+	}
+	expected = map[string]any{"type": "string", "format": "date-time", "readOnly": true}
+	if !reflect.DeepEqual(properties["_createdAt"], expected) {
+		t.Fatalf("expected: %v got: %v", expected, properties["_createdAt"])
+	}
+
+	insertProperties := schemas["ParentThingInsert"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := insertProperties["_version"]; ok {
+		t.Fatal("insert schema must not have _version")
+	}
+}
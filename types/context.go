@@ -0,0 +1,37 @@
+package types
+
+import "context"
+
+type contextKey string
+
+const (
+	userIdContextKey contextKey = "userId"
+	roleContextKey   contextKey = "role"
+)
+
+func WithUserId(ctx context.Context, userId any) context.Context {
+	return context.WithValue(ctx, userIdContextKey, userId)
+}
+
+func GetUserId(ctx context.Context) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	userId := ctx.Value(userIdContextKey)
+	if userId == nil {
+		return nil, false
+	}
+	return userId, true
+}
+
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleContextKey, role)
+}
+
+func GetRole(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	role, _ := ctx.Value(roleContextKey).(string)
+	return role
+}
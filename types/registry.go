@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Things registered without a Schema are placed in the registry Schema, so
+// one Registry per schema version lets several versions coexist.
+type Registry struct {
+	Schema string
+	mutex  sync.RWMutex
+	things map[string]ThingConfig
+}
+
+var DefaultRegistry = &Registry{}
+
+// A nil Registry stands for DefaultRegistry, so generators and executors can
+// leave their Registry field unset.
+func (r *Registry) orDefault() *Registry {
+	if r == nil {
+		return DefaultRegistry
+	}
+	return r
+}
+
+func (r *Registry) Register(thing ThingConfig) {
+	r = r.orDefault()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.things == nil {
+		r.things = map[string]ThingConfig{}
+	}
+	if thing.Schema == "" {
+		thing.Schema = r.Schema
+	}
+	r.things[thing.Name] = thing
+}
+
+func (r *Registry) RegisterStruct(value any) (ThingConfig, error) {
+	thing, err := GetStructThingConfig(value)
+	if err != nil {
+		return ThingConfig{}, err
+	}
+
+	r.Register(thing)
+	return thing, nil
+}
+
+func (r *Registry) Clear() {
+	r = r.orDefault()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.things = map[string]ThingConfig{}
+}
+
+func (r *Registry) GetAll() []ThingConfig {
+	r = r.orDefault()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	things := maps.Values(r.things)
+	slices.SortFunc(things, func(a, b ThingConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return things
+}
+
+func (r *Registry) Get(name string) (ThingConfig, error) {
+	r = r.orDefault()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	thing, ok := r.things[name]
+	if !ok {
+		return ThingConfig{}, fmt.Errorf("thingConfig: %s doesn't exists", name)
+	}
+	return thing, nil
+}
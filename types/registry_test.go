@@ -0,0 +1,120 @@
+package types_test
+
+import (
+	"fmt"
+	"json2sql/types"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := &types.Registry{}
+	registry.Register(types.ThingConfig{Name: "b"})
+	registry.Register(types.ThingConfig{Name: "a"})
+
+	thing, err := registry.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thing.Name != "a" {
+		t.Fatalf("expected: a, got: %s", thing.Name)
+	}
+
+	things := registry.GetAll()
+	if len(things) != 2 || things[0].Name != "a" || things[1].Name != "b" {
+		t.Fatalf("expected things a, b, got: %v", things)
+	}
+
+	registry.Clear()
+	_, err = registry.Get("a")
+	if err == nil {
+		t.Fatal("expected error after clear")
+	}
+}
+
+func TestRegistryIsIndependent(t *testing.T) {
+	types.Clear()
+	registry := &types.Registry{}
+	registry.Register(types.ThingConfig{Name: "local"})
+
+	_, err := types.Get("local")
+	if err == nil {
+		t.Fatal("expected local thing to be missing from default registry")
+	}
+
+	types.Register(types.ThingConfig{Name: "global"})
+	_, err = registry.Get("global")
+	if err == nil {
+		t.Fatal("expected global thing to be missing from local registry")
+	}
+}
+
+func TestNilRegistryUsesDefault(t *testing.T) {
+	types.Clear()
+	types.Register(types.ThingConfig{Name: "global"})
+
+	var registry *types.Registry
+	_, err := registry.Get("global")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryConcurrentAccess(t *testing.T) {
+	registry := &types.Registry{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		name := fmt.Sprintf("thing%d", i)
+		go func() {
+			defer wg.Done()
+			registry.Register(types.ThingConfig{Name: name})
+		}()
+		go func() {
+			defer wg.Done()
+			registry.Get(name)
+			registry.GetAll()
+		}()
+	}
+	wg.Wait()
+
+	things := registry.GetAll()
+	if len(things) != 50 {
+		t.Fatalf("expected: 50 things, got: %d", len(things))
+	}
+}
+
+func TestRegistrySchema(t *testing.T) {
+	registry := &types.Registry{Schema: "v2"}
+	registry.Register(types.ThingConfig{Name: "parentThing"})
+	registry.Register(types.ThingConfig{Name: "otherThing", Schema: "shared"})
+
+	thing, err := registry.Get("parentThing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thing.GetTableName() != `"v2"."parent_thing"` {
+		t.Fatalf(`expected: "v2"."parent_thing", got: %s`, thing.GetTableName())
+	}
+	if thing.GetHistoryTableName() != `"v2"."parent_thing_history"` {
+		t.Fatalf(`expected: "v2"."parent_thing_history", got: %s`, thing.GetHistoryTableName())
+	}
+
+	thing, err = registry.Get("otherThing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thing.GetTableName() != `"shared"."other_thing"` {
+		t.Fatalf(`expected: "shared"."other_thing", got: %s`, thing.GetTableName())
+	}
+}
+
+func TestGetTableNameWithoutSchema(t *testing.T) {
+	thing := types.ThingConfig{Name: "parentThing"}
+	if thing.GetTableName() != `"parent_thing"` {
+		t.Fatalf(`expected: "parent_thing", got: %s`, thing.GetTableName())
+	}
+	if thing.GetBaseTableName() != "parent_thing" {
+		t.Fatalf("expected: parent_thing, got: %s", thing.GetBaseTableName())
+	}
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (tc *ThingConfig) ScanInto(rows []map[string]any, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be pointer to slice")
+	}
+
+	sliceValue := destValue.Elem()
+	itemType := sliceValue.Type().Elem()
+	if itemType.Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be pointer to slice of structs")
+	}
+	fieldIndexes := getStructFieldIndexes(itemType)
+
+	for _, row := range rows {
+		hydratedRow, err := tc.Hydrate(row)
+		if err != nil {
+			return err
+		}
+
+		item := reflect.New(itemType).Elem()
+		for key, value := range hydratedRow {
+			index, ok := fieldIndexes[key]
+			if !ok {
+				continue
+			}
+
+			err := setValue(item.Field(index), value)
+			if err != nil {
+				return fmt.Errorf("field: %s %w", key, err)
+			}
+		}
+		sliceValue.Set(reflect.Append(sliceValue, item))
+	}
+
+	return nil
+}
+
+func getStructFieldIndexes(structType reflect.Type) map[string]int {
+	result := map[string]int{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() || field.Tag.Get(structTagName) == "-" {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" && field.Tag.Get(structTagName) == "" {
+			continue
+		}
+		result[getStructFieldName(field)] = i
+	}
+	return result
+}
+
+func setValue(target reflect.Value, value any) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.Kind() == reflect.Pointer {
+		elem := reflect.New(target.Type().Elem())
+		err := setValue(elem.Elem(), value)
+		if err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(target.Type()) {
+		target.Set(source)
+		return nil
+	}
+
+	if target.Type() == timeType {
+		if s, ok := value.(string); ok {
+			for _, layout := range []string{time.RFC3339Nano, time.DateOnly, time.TimeOnly} {
+				parsed, err := time.Parse(layout, s)
+				if err == nil {
+					target.Set(reflect.ValueOf(parsed))
+					return nil
+				}
+			}
+		}
+	}
+
+	if isSameKind(source.Type(), target.Type()) && source.Type().ConvertibleTo(target.Type()) {
+		if isLossyConversion(source, target.Type()) {
+			return fmt.Errorf("value: %v cannot be converted to %s without losing precision", value, target.Type())
+		}
+		target.Set(source.Convert(target.Type()))
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target.Addr().Interface())
+}
+
+func isLossyConversion(source reflect.Value, targetType reflect.Type) bool {
+	if !source.CanFloat() {
+		return false
+	}
+
+	f := source.Float()
+	if f != math.Trunc(f) {
+		return targetType.Kind() >= reflect.Int && targetType.Kind() <= reflect.Uintptr
+	}
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f < math.MinInt64 || f >= math.MaxInt64 || reflect.Zero(targetType).OverflowInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return f < 0 || f >= math.MaxUint64 || reflect.Zero(targetType).OverflowUint(uint64(f))
+	}
+	return false
+}
+
+func isSameKind(a reflect.Type, b reflect.Type) bool {
+	switch {
+	case a.Kind() == reflect.String || b.Kind() == reflect.String:
+		return a.Kind() == b.Kind()
+	case a.Kind() == reflect.Slice || b.Kind() == reflect.Slice:
+		return false
+	default:
+		return a.Kind() <= reflect.Complex128 && b.Kind() <= reflect.Complex128
+	}
+}
@@ -0,0 +1,314 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+const structTagName = "json2sql"
+
+func RegisterStruct(value any) (ThingConfig, error) {
+	return DefaultRegistry.RegisterStruct(value)
+}
+
+func GetStructThingConfig(value any) (ThingConfig, error) {
+	structType := reflect.TypeOf(value)
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return ThingConfig{}, fmt.Errorf("value: %v is not struct", value)
+	}
+
+	thing := ThingConfig{
+		Name:   strcase.ToLowerCamel(structType.Name()),
+		Fields: map[string]FieldConfig{},
+	}
+	var errs []error
+
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		tag, hasTag := structField.Tag.Lookup(structTagName)
+
+		if structField.Name == "_" {
+			if hasTag {
+				err := setThingOptions(&thing, tag)
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+
+		if !structField.IsExported() || tag == "-" {
+			continue
+		}
+
+		fieldName := getStructFieldName(structField)
+		// System fields like "_version" are filled by ScanInto but have no
+		// column of their own.
+		if IsSystemFieldName(fieldName) {
+			continue
+		}
+		field, err := getStructFieldConfig(fieldName, structField.Type, tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field: %s %w", structField.Name, err))
+			continue
+		}
+		thing.Fields[fieldName] = field
+	}
+
+	if thing.Name == "" {
+		errs = append(errs, fmt.Errorf("struct has no name, set it with %s:\"thing=name\"", structTagName))
+	}
+	if _, err := thing.GetPrimaryKey(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return thing, errors.Join(errs...)
+}
+
+func GetStructValues(value any) (map[string]any, error) {
+	structValue := reflect.ValueOf(value)
+	for structValue.Kind() == reflect.Pointer {
+		structValue = structValue.Elem()
+	}
+
+	thing, err := GetStructThingConfig(structValue.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		field, ok := thing.Fields[getStructFieldName(structField)]
+		if !ok || structField.Tag.Get(structTagName) == "-" {
+			continue
+		}
+		if field.Type == PRIMARY_KEY || (field.Type == RELATION && field.Relation.Type != MANY_TO_ONE) {
+			continue
+		}
+
+		fieldValue := structValue.Field(i)
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if (fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Map || fieldValue.Kind() == reflect.Interface) && fieldValue.IsNil() {
+			continue
+		}
+
+		values[field.Name] = fieldValue.Interface()
+		if fieldValue.Kind() == reflect.String {
+			values[field.Name] = fieldValue.String()
+		}
+	}
+
+	return values, nil
+}
+
+func getStructFieldName(structField reflect.StructField) string {
+	name, _, _ := strings.Cut(structField.Tag.Get(structTagName), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+
+	name, _, _ = strings.Cut(structField.Tag.Get("json"), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+
+	return strcase.ToLowerCamel(structField.Name)
+}
+
+func setThingOptions(thing *ThingConfig, tag string) error {
+	for _, option := range splitTag(tag) {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "thing":
+			thing.Name = value
+		case "schema":
+			thing.Schema = value
+		case "softDelete":
+			thing.SoftDelete = true
+		case "timestamps":
+			thing.Timestamps = true
+		case "versioned":
+			thing.Versioned = true
+		case "auditable":
+			thing.Auditable = true
+		case "assignedToUser":
+			thing.Constraints.AssignedToUser = true
+		case "searchConfig":
+			thing.SearchConfig = value
+		case "unique":
+			thing.Constraints.Unique = append(thing.Constraints.Unique, strings.Split(value, "|"))
+		case "":
+		default:
+			return fmt.Errorf("thing option: %s is not supported", key)
+		}
+	}
+	return nil
+}
+
+func getStructFieldConfig(fieldName string, fieldType reflect.Type, tag string) (FieldConfig, error) {
+	field := FieldConfig{Name: fieldName}
+	var errs []error
+
+	options := splitTag(tag)
+	if len(options) > 0 {
+		options = options[1:]
+	}
+	for _, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+		var err error
+		switch key {
+		case "type":
+			field.Type = FieldType(value)
+		case "primaryKey":
+			field.Type = PRIMARY_KEY
+		case "notNull":
+			field.NotNull = true
+		case "unique":
+			field.Unique = true
+		case "default":
+			field.Default = value
+		case "check":
+			field.Check = value
+		case "maxLength":
+			field.MaxLength, err = strconv.Atoi(value)
+		case "pattern":
+			field.Pattern = value
+		case "precision":
+			field.Precision, err = strconv.Atoi(value)
+		case "scale":
+			field.Scale, err = strconv.Atoi(value)
+		case "min":
+			field.Min, err = parseFloat64Pointer(value)
+		case "max":
+			field.Max, err = parseFloat64Pointer(value)
+		case "enum":
+			field.Type = ENUM
+			field.EnumValues = strings.Split(value, "|")
+		case "arrayOf":
+			field.Type = ARRAY
+			field.ArrayOf = FieldType(value)
+		case "thing":
+			field.Type = THING
+			field.TypeThingName = value
+		case "relation":
+			field.Type = RELATION
+			field.Relation, err = parseRelation(value)
+		case "searchable":
+			field.Searchable = true
+		case "hidden":
+			field.Access.Hidden = true
+		default:
+			err = fmt.Errorf("option: %s is not supported", key)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if field.Type == "" {
+		field.Type, field.ArrayOf = getStructFieldType(fieldType)
+	}
+	if field.Type == "" {
+		errs = append(errs, fmt.Errorf("type: %s cannot be mapped, set it with %s:\",type=...\"", fieldType, structTagName))
+	}
+
+	return field, errors.Join(errs...)
+}
+
+func getStructFieldType(fieldType reflect.Type) (FieldType, FieldType) {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType == timeType {
+		return TIMESTAMP, ""
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return STRING, ""
+	case reflect.Bool:
+		return BOOLEAN, ""
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return BIGINT, ""
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return INTEGER, ""
+	case reflect.Float32, reflect.Float64:
+		return NUMBER, ""
+	case reflect.Slice:
+		// []byte and json.RawMessage hold encoded data, not a list of numbers.
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return JSON, ""
+		}
+		itemType, _ := getStructFieldType(fieldType.Elem())
+		if itemType == "" || itemType == JSON {
+			return JSON, ""
+		}
+		return ARRAY, itemType
+	case reflect.Map, reflect.Struct, reflect.Interface:
+		return JSON, ""
+	}
+	return "", ""
+}
+
+func parseRelation(value string) (ThingRelation, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ThingRelation{}, fmt.Errorf("relation: %s must be TYPE:otherThingName[:otherFieldName]", value)
+	}
+
+	relation := ThingRelation{
+		Type:           ThingRelationType(parts[0]),
+		OtherThingName: parts[1],
+	}
+	if len(parts) == 3 {
+		relation.OtherFieldName = parts[2]
+	}
+	return relation, nil
+}
+
+func parseFloat64Pointer(value string) (*float64, error) {
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Commas inside quotes or parentheses belong to the option value, so checks
+// like check=(status IN ('a', 'b')) can be written in a tag.
+func splitTag(tag string) []string {
+	result := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+	for i, char := range tag {
+		switch {
+		case char == '\'':
+			quoted = !quoted
+		case char == '(' && !quoted:
+			depth++
+		case char == ')' && !quoted:
+			depth--
+		case char == ',' && !quoted && depth == 0:
+			result = append(result, strings.TrimSpace(tag[start:i]))
+			start = i + 1
+		}
+	}
+	return append(result, strings.TrimSpace(tag[start:]))
+}
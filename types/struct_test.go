@@ -0,0 +1,237 @@
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"json2sql/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Author struct {
+	_         struct{}  `json2sql:"thing=author,schema=library,timestamps,softDelete"`
+	Id        int64     `json2sql:"id,primaryKey"`
+	Name      string    `json2sql:",notNull,maxLength=100" json:"name"`
+	Email     *string   `json2sql:",unique,pattern=^.+@.+$"`
+	Born      time.Time `json2sql:",type=DATE"`
+	Rating    float64   `json2sql:",min=0,max=5"`
+	Sales     int
+	Status    string `json2sql:",enum=active|retired,default='active',check=(status IN ('active', 'retired'))"`
+	Tags      []string
+	Metadata  map[string]any
+	Books     []Book `json2sql:",relation=ONE_TO_MANY:book:author"`
+	Ignored   string `json2sql:"-"`
+	unexposed string
+}
+
+type Book struct {
+	PrimaryKey int64  `json:"primaryKey" json2sql:",primaryKey"`
+	Title      string `json:"title"`
+	Author     *int64 `json2sql:"author,relation=MANY_TO_ONE:author:books"`
+}
+
+func TestGetStructThingConfig(t *testing.T) {
+	thing, err := types.GetStructThingConfig(Author{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	minRating := 0.0
+	maxRating := 5.0
+	expected := types.ThingConfig{
+		Name:       "author",
+		Schema:     "library",
+		Timestamps: true,
+		SoftDelete: true,
+		Fields: map[string]types.FieldConfig{
+			"id":    {Name: "id", Type: types.PRIMARY_KEY},
+			"name":  {Name: "name", Type: types.STRING, NotNull: true, MaxLength: 100},
+			"email": {Name: "email", Type: types.STRING, Unique: true, Pattern: "^.+@.+$"},
+			"born":  {Name: "born", Type: types.DATE},
+			"sales": {Name: "sales", Type: types.BIGINT},
+			"rating": {
+				Name: "rating",
+				Type: types.NUMBER,
+				Min:  &minRating,
+				Max:  &maxRating,
+			},
+			"status": {
+				Name:       "status",
+				Type:       types.ENUM,
+				EnumValues: []string{"active", "retired"},
+				Default:    "'active'",
+				Check:      "(status IN ('active', 'retired'))",
+			},
+			"tags":     {Name: "tags", Type: types.ARRAY, ArrayOf: types.STRING},
+			"metadata": {Name: "metadata", Type: types.JSON},
+			"books": {
+				Name: "books",
+				Type: types.RELATION,
+				Relation: types.ThingRelation{
+					Type:           types.ONE_TO_MANY,
+					OtherThingName: "book",
+					OtherFieldName: "author",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(thing, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, thing)
+	}
+}
+
+func TestRegisterStruct(t *testing.T) {
+	types.Clear()
+	_, err := types.RegisterStruct(&Book{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thing, err := types.Get("book")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if thing.Fields["title"].Type != types.STRING || thing.Fields["author"].Relation.Type != types.MANY_TO_ONE {
+		t.Fatalf("unexpected thing: %#v", thing)
+	}
+}
+
+func TestGetStructThingConfigErrors(t *testing.T) {
+	type Invalid struct {
+		Name    string `json2sql:",maxLength=long"`
+		Channel chan int
+		Counter uint64
+	}
+
+	_, err := types.GetStructThingConfig(Invalid{})
+
+	expectedError := `field: Name strconv.Atoi: parsing "long": invalid syntax
+field: Channel type: chan int cannot be mapped, set it with json2sql:",type=..."
+field: Counter type: uint64 cannot be mapped, set it with json2sql:",type=..."
+thing: invalid has no primary key`
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestGetStructThingConfigBytes(t *testing.T) {
+	type Document struct {
+		Id      int64 `json2sql:",primaryKey"`
+		Content []byte
+		Raw     json.RawMessage
+	}
+
+	thing, err := types.GetStructThingConfig(Document{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"content", "raw"} {
+		field := thing.Fields[name]
+		if field.Type != types.JSON || field.ArrayOf != "" {
+			t.Fatalf("expected field: %s to be JSON got: %v", name, field)
+		}
+	}
+}
+
+func TestGetStructValues(t *testing.T) {
+	author := int64(1)
+	values, err := types.GetStructValues(Book{PrimaryKey: 2, Title: "Earthsea", Author: &author})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{"title": "Earthsea", "author": int64(1)}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected: %v got: %v", expected, values)
+	}
+}
+
+func TestScanInto(t *testing.T) {
+	thing, err := types.GetStructThingConfig(Author{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := []map[string]any{{
+		"id":     int64(1),
+		"name":   "Ursula",
+		"email":  nil,
+		"rating": []uint8("4.5"),
+		"tags":   []uint8(`{a,b}`),
+	}}
+
+	authors := []Author{}
+	err = thing.ScanInto(rows, &authors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Author{{Id: 1, Name: "Ursula", Rating: 4.5, Tags: []string{"a", "b"}}}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Fatalf("expected: %#v got: %#v", expected, authors)
+	}
+}
+
+func TestScanIntoLossyNumber(t *testing.T) {
+	type Counter struct {
+		Id    int64 `json2sql:",primaryKey"`
+		Count int8  `json2sql:",type=NUMBER"`
+	}
+
+	thing, err := types.GetStructThingConfig(Counter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, count := range []any{1.5, 300.0} {
+		counters := []Counter{}
+		err = thing.ScanInto([]map[string]any{{"id": int64(1), "count": count}}, &counters)
+
+		expectedError := fmt.Sprintf("field: count value: %v cannot be converted to int8 without losing precision", count)
+		if err == nil {
+			t.Fatal("error expected")
+		} else if err.Error() != expectedError {
+			t.Fatalf("expected error: %s got: %s", expectedError, err)
+		}
+	}
+
+	counters := []Counter{}
+	err = thing.ScanInto([]map[string]any{{"id": int64(1), "count": 3.0}}, &counters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counters, []Counter{{Id: 1, Count: 3}}) {
+		t.Fatalf("unexpected counters: %#v", counters)
+	}
+}
+
+func TestScanIntoSystemFields(t *testing.T) {
+	type Note struct {
+		_       struct{} `json2sql:"thing=note,versioned"`
+		Id      int64    `json2sql:",primaryKey"`
+		Version int64    `json:"_version"`
+	}
+
+	thing, err := types.GetStructThingConfig(Note{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := thing.Fields["_version"]; ok {
+		t.Fatal("expected _version not to be a field")
+	}
+
+	notes := []Note{}
+	err = thing.ScanInto([]map[string]any{{"id": int64(1), "_version": int64(3)}}, &notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(notes, []Note{{Id: 1, Version: 3}}) {
+		t.Fatalf("unexpected notes: %#v", notes)
+	}
+}
@@ -1,42 +1,137 @@
 package types
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/iancoleman/strcase"
+	"github.com/lib/pq"
 	"golang.org/x/exp/maps"
 )
 
 const (
-	PRIMARY_KEY FieldType         = "PRIMARY_KEY"
-	STRING      FieldType         = "STRING"
-	NUMBER      FieldType         = "NUMBER"
-	BOOLEAN     FieldType         = "BOOLEAN"
-	DATE        FieldType         = "DATE"
-	THING       FieldType         = "THING"
-	RELATION    FieldType         = "RELATION"
-	ONE_TO_MANY ThingRelationType = "ONE_TO_MANY"
-	MANY_TO_ONE ThingRelationType = "MANY_TO_ONE"
+	PRIMARY_KEY  FieldType         = "PRIMARY_KEY"
+	STRING       FieldType         = "STRING"
+	NUMBER       FieldType         = "NUMBER"
+	BOOLEAN      FieldType         = "BOOLEAN"
+	DATE         FieldType         = "DATE"
+	INTEGER      FieldType         = "INTEGER"
+	BIGINT       FieldType         = "BIGINT"
+	DECIMAL      FieldType         = "DECIMAL"
+	TIMESTAMP    FieldType         = "TIMESTAMP"
+	TIME         FieldType         = "TIME"
+	UUID         FieldType         = "UUID"
+	JSON         FieldType         = "JSON"
+	ENUM         FieldType         = "ENUM"
+	ARRAY        FieldType         = "ARRAY"
+	THING        FieldType         = "THING"
+	RELATION     FieldType         = "RELATION"
+	ONE_TO_MANY  ThingRelationType = "ONE_TO_MANY"
+	MANY_TO_ONE  ThingRelationType = "MANY_TO_ONE"
+	MANY_TO_MANY ThingRelationType = "MANY_TO_MANY"
+)
+
+const (
+	READ   FieldPermission = "READ"
+	INSERT FieldPermission = "INSERT"
+	UPDATE FieldPermission = "UPDATE"
+)
+
+const (
+	POSTGRES Dialect = "POSTGRES"
+	SQLITE   Dialect = "SQLITE"
+)
+
+const (
+	OWNER_COLUMN_NAME = "owner_id"
+	OWNER_VALUE_NAME  = "_ownerId"
+
+	DELETED_AT_COLUMN_NAME = "deleted_at"
+	CREATED_AT_COLUMN_NAME = "created_at"
+	UPDATED_AT_COLUMN_NAME = "updated_at"
+
+	AUDIT_USER_SETTING_NAME = "json2sql.user_id"
+
+	SEARCH_VECTOR_COLUMN_NAME = "search_vector"
+
+	RELATED_ID_ALIAS = "_relatedId"
+
+	VERSION_COLUMN_NAME         = "version"
+	EXPECTED_VERSION_VALUE_NAME = "_expectedVersion"
+
+	VERSION_VALUE_NAME    = "_version"
+	CREATED_AT_VALUE_NAME = "_createdAt"
+	UPDATED_AT_VALUE_NAME = "_updatedAt"
 )
 
 type FieldType string
 type ThingRelationType string
+type FieldPermission string
+type Dialect string
 
 type ThingConfig struct {
-	Name        string                 `json:"name"`
-	Constraints ThingConstraints       `json:"constraints"`
-	Fields      map[string]FieldConfig `json:"fields"`
+	Name         string                 `json:"name"`
+	Schema       string                 `json:"schema"`
+	Constraints  ThingConstraints       `json:"constraints"`
+	Fields       map[string]FieldConfig `json:"fields"`
+	Indexes      []IndexConfig          `json:"indexes"`
+	SoftDelete   bool                   `json:"softDelete"`
+	Timestamps   bool                   `json:"timestamps"`
+	Versioned    bool                   `json:"versioned"`
+	Auditable    bool                   `json:"auditable"`
+	SearchConfig string                 `json:"searchConfig"`
 }
 
+type IndexConfig struct {
+	Name   string       `json:"name"`
+	Fields []IndexField `json:"fields"`
+	Unique bool         `json:"unique"`
+	Where  string       `json:"where"`
+}
+
+type IndexField struct {
+	Name       string `json:"name"`
+	Descending bool   `json:"descending"`
+}
+
+// Default and Check are raw SQL expressions pasted into the DDL, so string
+// literals must be quoted, e.g. "'draft'", and schema files providing them
+// must be trusted.
 type FieldConfig struct {
 	Name          string        `json:"name"`
 	Type          FieldType     `json:"type"`
 	TypeThingName string        `json:"typeThingName"`
 	Relation      ThingRelation `json:"relation"`
+	Access        FieldAccess   `json:"access"`
+	NotNull       bool          `json:"notNull"`
+	Unique        bool          `json:"unique"`
+	Default       string        `json:"default"`
+	Check         string        `json:"check"`
+	Min           *float64      `json:"min"`
+	Max           *float64      `json:"max"`
+	MaxLength     int           `json:"maxLength"`
+	Pattern       string        `json:"pattern"`
+	Precision     int           `json:"precision"`
+	Scale         int           `json:"scale"`
+	EnumValues    []string      `json:"enumValues"`
+	ArrayOf       FieldType     `json:"arrayOf"`
+	Searchable    bool          `json:"searchable"`
+}
+
+type FieldAccess struct {
+	Hidden      bool                         `json:"hidden"`
+	Permissions []FieldPermission            `json:"permissions"`
+	Roles       map[string][]FieldPermission `json:"roles"`
 }
 
 type ThingRelation struct {
@@ -45,18 +140,32 @@ type ThingRelation struct {
 	OtherFieldName string            `json:"otherFieldName"`
 }
 
+type JoinTable struct {
+	Name        string
+	Schema      string
+	ThingColumn string
+	OtherColumn string
+}
+
 type ThingConstraints struct {
-	AssignedToUser bool
+	AssignedToUser bool       `json:"assignedToUser"`
+	Unique         [][]string `json:"unique"`
 }
 
-var thingConfigMap = map[string]ThingConfig{}
+var systemColumnNames = map[string]string{
+	VERSION_VALUE_NAME:    VERSION_COLUMN_NAME,
+	CREATED_AT_VALUE_NAME: CREATED_AT_COLUMN_NAME,
+	UPDATED_AT_VALUE_NAME: UPDATED_AT_COLUMN_NAME,
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 
 func Register(thing ThingConfig) {
-	thingConfigMap[thing.Name] = thing
+	DefaultRegistry.Register(thing)
 }
 
 func Clear() {
-	thingConfigMap = map[string]ThingConfig{}
+	DefaultRegistry.Clear()
 }
 
 func (fc *FieldConfig) GetColumnName() string {
@@ -67,6 +176,252 @@ func (fc *FieldConfig) GetColumnName() string {
 	return result
 }
 
+func (ft FieldType) IsNumeric() bool {
+	return ft == NUMBER || ft == INTEGER || ft == BIGINT || ft == DECIMAL
+}
+
+func IsTime(value string) bool {
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func IsUUID(value string) bool {
+	return uuidRegexp.MatchString(value)
+}
+
+func (fc *FieldConfig) GetPrecision() (int, int) {
+	if fc.Precision <= 0 {
+		return 18, 4
+	}
+	return fc.Precision, fc.Scale
+}
+
+func (fc *FieldConfig) GetArrayItemField() FieldConfig {
+	return FieldConfig{Name: fc.Name, Type: fc.ArrayOf}
+}
+
+func ToSlice(value any) ([]any, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	result := []any{}
+	for i := 0; i < v.Len(); i++ {
+		result = append(result, v.Index(i).Interface())
+	}
+	return result, true
+}
+
+// IsArrayItemType reports whether GetArrayValue can convert arrays of
+// fieldType.
+func IsArrayItemType(fieldType FieldType) bool {
+	switch fieldType {
+	case STRING, NUMBER, INTEGER, BIGINT:
+		return true
+	default:
+		return false
+	}
+}
+
+func (fc *FieldConfig) GetArrayValue(value any) (driver.Valuer, error) {
+	items, ok := ToSlice(value)
+	if !ok {
+		return nil, fmt.Errorf("value: %v is not array", value)
+	}
+
+	switch fc.ArrayOf {
+	case STRING:
+		result := pq.StringArray{}
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("value: %v is not string", item)
+			}
+			result = append(result, s)
+		}
+		return result, nil
+	case NUMBER:
+		result := pq.Float64Array{}
+		for _, item := range items {
+			f, ok := ToFloat64(item)
+			if !ok {
+				return nil, fmt.Errorf("value: %v is not number", item)
+			}
+			result = append(result, f)
+		}
+		return result, nil
+	case INTEGER, BIGINT:
+		result := pq.Int64Array{}
+		for _, item := range items {
+			n, ok := ToInt64(item)
+			if !ok {
+				return nil, fmt.Errorf("value: %v is not integer", item)
+			}
+			result = append(result, n)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("array of: %s is not supported", fc.ArrayOf)
+}
+
+func ToFloat64(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// ToInt64 converts integer values without going through float64, so BIGINT
+// values above 2^53 keep their precision. Floats are accepted when integral.
+func ToInt64(value any) (int64, bool) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), uint64(n) <= math.MaxInt64
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case json.Number:
+		i, err := n.Int64()
+		if err == nil {
+			return i, true
+		}
+	}
+
+	f, ok := ToFloat64(value)
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func (fc *FieldConfig) GetEnumConstraintName() string {
+	return fc.GetColumnName() + "_enum_check"
+}
+
+func (fc *FieldConfig) GetValueName() string {
+	if fc.Type == THING || fc.Type == RELATION {
+		return fc.Name + "Id"
+	}
+	return fc.Name
+}
+
+func (fc *FieldConfig) IsAllowed(permission FieldPermission, role string) bool {
+	if fc.Access.Hidden && permission == READ {
+		return false
+	}
+
+	rolePermissions, ok := fc.Access.Roles[role]
+	if ok {
+		return slices.Contains(rolePermissions, permission)
+	}
+
+	if fc.Access.Permissions != nil {
+		return slices.Contains(fc.Access.Permissions, permission)
+	}
+
+	return true
+}
+
+func (tc *ThingConfig) GetPrimaryKey() (FieldConfig, error) {
+	for _, field := range tc.GetFields() {
+		if field.Type == PRIMARY_KEY {
+			return field, nil
+		}
+	}
+	return FieldConfig{}, fmt.Errorf("thing: %s has no primary key", tc.Name)
+}
+
+func (tc *ThingConfig) GetOwnerId(ctx context.Context) (any, error) {
+	userId, ok := GetUserId(ctx)
+	if !ok {
+		return nil, fmt.Errorf("thing: %s is assigned to user but no user id in context", tc.Name)
+	}
+	return userId, nil
+}
+
+// GetJoinTable returns the same join table from both sides of a relation.
+// Its name and schema come from the side whose name sorts first.
+func (tc *ThingConfig) GetJoinTable(field FieldConfig, otherThing ThingConfig) JoinTable {
+	thingTable := tc.GetBaseTableName()
+	otherTable := strcase.ToSnake(field.Relation.OtherThingName)
+
+	name := thingTable + "_" + strcase.ToSnake(field.Name)
+	schema := tc.Schema
+	canonicalFieldName := field.Name
+	if field.Relation.OtherFieldName != "" {
+		otherName := otherTable + "_" + strcase.ToSnake(field.Relation.OtherFieldName)
+		if otherName < name {
+			name = otherName
+			schema = otherThing.Schema
+			canonicalFieldName = field.Relation.OtherFieldName
+		}
+	}
+
+	joinTable := JoinTable{
+		Name:        name,
+		Schema:      schema,
+		ThingColumn: thingTable + "_id",
+		OtherColumn: otherTable + "_id",
+	}
+
+	if joinTable.ThingColumn == joinTable.OtherColumn {
+		relatedColumn := strcase.ToSnake(canonicalFieldName) + "_id"
+		if canonicalFieldName == field.Name {
+			joinTable.OtherColumn = relatedColumn
+		} else {
+			joinTable.ThingColumn = relatedColumn
+		}
+	}
+
+	return joinTable
+}
+
 func (tc *ThingConfig) GetField(name string) (FieldConfig, error) {
 	fieldConfig, ok := tc.Fields[name]
 	if !ok {
@@ -75,6 +430,41 @@ func (tc *ThingConfig) GetField(name string) (FieldConfig, error) {
 	return fieldConfig, nil
 }
 
+// GetSystemFields returns read-only fields for the columns the generators
+// maintain, e.g. "_version", which a versioned update has to send back.
+func (tc *ThingConfig) GetSystemFields() []FieldConfig {
+	readOnly := FieldAccess{Permissions: []FieldPermission{READ}}
+	fields := []FieldConfig{}
+	if tc.Timestamps {
+		fields = append(fields,
+			FieldConfig{Name: CREATED_AT_VALUE_NAME, Type: TIMESTAMP, NotNull: true, Access: readOnly},
+			FieldConfig{Name: UPDATED_AT_VALUE_NAME, Type: TIMESTAMP, NotNull: true, Access: readOnly})
+	}
+	if tc.Versioned {
+		fields = append(fields, FieldConfig{Name: VERSION_VALUE_NAME, Type: INTEGER, NotNull: true, Access: readOnly})
+	}
+	return fields
+}
+
+func (tc *ThingConfig) GetSystemField(name string) (FieldConfig, bool) {
+	for _, field := range tc.GetSystemFields() {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FieldConfig{}, false
+}
+
+// GetSystemColumnName returns the column selected by a system field name.
+func GetSystemColumnName(name string) string {
+	return systemColumnNames[name]
+}
+
+func IsSystemFieldName(name string) bool {
+	_, ok := systemColumnNames[name]
+	return ok
+}
+
 func (tc *ThingConfig) GetFields() []FieldConfig {
 	fields := maps.Values(tc.Fields)
 	slices.SortFunc(fields, func(a, b FieldConfig) int {
@@ -83,10 +473,50 @@ func (tc *ThingConfig) GetFields() []FieldConfig {
 	return fields
 }
 
+// GetTableName returns the quoted table name, qualified with the Postgres
+// schema when the thing has one, e.g. "v2"."parent_thing".
 func (tc *ThingConfig) GetTableName() string {
+	return QuoteName(tc.Schema, tc.GetBaseTableName())
+}
+
+// GetBaseTableName returns the unquoted table name without schema, used to
+// derive index, constraint and join table names.
+func (tc *ThingConfig) GetBaseTableName() string {
 	return strcase.ToSnake(tc.Name)
 }
 
+func (tc *ThingConfig) GetHistoryTableName() string {
+	return QuoteName(tc.Schema, tc.GetBaseTableName()+"_history")
+}
+
+func (jt JoinTable) GetTableName() string {
+	return QuoteName(jt.Schema, jt.Name)
+}
+
+func QuoteName(schema string, name string) string {
+	if schema == "" {
+		return fmt.Sprintf(`"%s"`, name)
+	}
+	return fmt.Sprintf(`"%s"."%s"`, schema, name)
+}
+
+func (tc *ThingConfig) GetSearchableFields() []FieldConfig {
+	result := []FieldConfig{}
+	for _, field := range tc.GetFields() {
+		if field.Searchable {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func (tc *ThingConfig) GetSearchConfig() string {
+	if tc.SearchConfig == "" {
+		return "simple"
+	}
+	return tc.SearchConfig
+}
+
 func (tc *ThingConfig) GetFieldsNames() []string {
 	result := []string{}
 	for _, field := range tc.Fields {
@@ -95,12 +525,12 @@ func (tc *ThingConfig) GetFieldsNames() []string {
 	return result
 }
 
+func GetAll() []ThingConfig {
+	return DefaultRegistry.GetAll()
+}
+
 func Get(name string) (ThingConfig, error) {
-	thing, ok := thingConfigMap[name]
-	if !ok {
-		return ThingConfig{}, fmt.Errorf("thingConfig: %s doesn't exists", name)
-	}
-	return thing, nil
+	return DefaultRegistry.Get(name)
 }
 
 func (fc FieldConfig) GetBool(valuesMap map[string]any) (bool, error) {
@@ -166,3 +596,223 @@ func (fc FieldConfig) GetDate(valuesMap map[string]any) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("value: %v is not date", v)
 	}
 }
+
+func (fc FieldConfig) GetInt64(valuesMap map[string]any) (int64, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return 0, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case []uint8:
+		str := string(v)
+		integer, err := strconv.ParseInt(str, 10, 64)
+		return integer, err
+	default:
+		return 0, fmt.Errorf("value: %v is not int64", v)
+	}
+}
+
+func (fc FieldConfig) GetDecimal(valuesMap map[string]any) (string, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return "", fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []uint8:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("value: %v is not decimal", v)
+	}
+}
+
+func (fc FieldConfig) GetTimestamp(valuesMap map[string]any) (time.Time, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return time.Time{}, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	default:
+		return time.Time{}, fmt.Errorf("value: %v is not timestamp", v)
+	}
+}
+
+func (fc FieldConfig) GetTime(valuesMap map[string]any) (string, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return "", fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []uint8:
+		return string(v), nil
+	case time.Time:
+		return v.Format(time.TimeOnly), nil
+	default:
+		return "", fmt.Errorf("value: %v is not time", v)
+	}
+}
+
+func (fc FieldConfig) GetUUID(valuesMap map[string]any) (string, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return "", fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []uint8:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("value: %v is not uuid", v)
+	}
+}
+
+func (fc FieldConfig) GetJSON(valuesMap map[string]any) (any, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []uint8:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("value: %v is not json", v)
+	}
+
+	var result any
+	err := json.Unmarshal(data, &result)
+	return result, err
+}
+
+func (fc FieldConfig) GetStringArray(valuesMap map[string]any) ([]string, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	result := pq.StringArray{}
+	err := result.Scan(value)
+	return result, err
+}
+
+func (fc FieldConfig) GetFloat64Array(valuesMap map[string]any) ([]float64, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	result := pq.Float64Array{}
+	err := result.Scan(value)
+	return result, err
+}
+
+func (fc FieldConfig) GetInt64Array(valuesMap map[string]any) ([]int64, error) {
+	fieldName := fc.Name
+	value, ok := valuesMap[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("key: %s is not present in valuesMap", fieldName)
+	}
+
+	result := pq.Int64Array{}
+	err := result.Scan(value)
+	return result, err
+}
+
+func (fc FieldConfig) GetValue(valuesMap map[string]any) (any, error) {
+	value, ok := valuesMap[fc.Name]
+	if !ok {
+		return nil, fmt.Errorf("key: %s is not present in valuesMap", fc.Name)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch fc.Type {
+	case BOOLEAN:
+		return fc.GetBool(valuesMap)
+	case STRING, ENUM:
+		if v, ok := value.([]uint8); ok {
+			return string(v), nil
+		}
+		return fc.GetString(valuesMap)
+	case NUMBER:
+		return fc.GetFloat64(valuesMap)
+	case PRIMARY_KEY, INTEGER, BIGINT, THING, RELATION:
+		return fc.GetInt64(valuesMap)
+	case DECIMAL:
+		return fc.GetDecimal(valuesMap)
+	case DATE:
+		date, err := fc.GetDate(valuesMap)
+		return date.Format(time.DateOnly), err
+	case TIMESTAMP:
+		return fc.GetTimestamp(valuesMap)
+	case TIME:
+		return fc.GetTime(valuesMap)
+	case UUID:
+		return fc.GetUUID(valuesMap)
+	case JSON:
+		return fc.GetJSON(valuesMap)
+	case ARRAY:
+		switch fc.ArrayOf {
+		case INTEGER, BIGINT:
+			return fc.GetInt64Array(valuesMap)
+		case NUMBER, DECIMAL:
+			return fc.GetFloat64Array(valuesMap)
+		default:
+			return fc.GetStringArray(valuesMap)
+		}
+	default:
+		return value, nil
+	}
+}
+
+func (tc *ThingConfig) Hydrate(valuesMap map[string]any) (map[string]any, error) {
+	result := map[string]any{}
+	var errs []error
+
+	for key := range valuesMap {
+		field, err := tc.GetField(key)
+		if systemField, ok := tc.GetSystemField(key); ok {
+			field, err = systemField, nil
+		}
+		if err != nil {
+			result[key] = valuesMap[key]
+			continue
+		}
+
+		value, err := field.GetValue(map[string]any{field.Name: valuesMap[key]})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field: %s %w", field.Name, err))
+			continue
+		}
+		result[key] = value
+	}
+
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"encoding/json"
+	"json2sql/types"
+	"testing"
+)
+
+func TestToFloat64(t *testing.T) {
+	for _, value := range []any{1.5, float32(1.5), 2, int64(2), uint8(2), json.Number("1.5")} {
+		if _, ok := types.ToFloat64(value); !ok {
+			t.Fatalf("expected %v (%T) to be number", value, value)
+		}
+	}
+
+	for _, value := range []any{"1.5", true, nil, json.Number("x")} {
+		if _, ok := types.ToFloat64(value); ok {
+			t.Fatalf("expected %v (%T) not to be number", value, value)
+		}
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	for value, expected := range map[any]int64{
+		int64(9007199254740993):         9007199254740993,
+		json.Number("9007199254740993"): 9007199254740993,
+		uint32(7):                       7,
+		2.0:                             2,
+		json.Number("2.0"):              2,
+	} {
+		n, ok := types.ToInt64(value)
+		if !ok || n != expected {
+			t.Fatalf("expected %v (%T) to be %d got: %d", value, value, expected, n)
+		}
+	}
+
+	for _, value := range []any{1.5, uint64(1 << 63), "1", json.Number("1.5"), nil} {
+		if _, ok := types.ToInt64(value); ok {
+			t.Fatalf("expected %v (%T) not to be integer", value, value)
+		}
+	}
+}
+
+func TestGetJoinTableSchema(t *testing.T) {
+	member := types.ThingConfig{
+		Name:   "member",
+		Schema: "people",
+		Fields: map[string]types.FieldConfig{
+			"groups": {
+				Name:     "groups",
+				Type:     types.RELATION,
+				Relation: types.ThingRelation{Type: types.MANY_TO_MANY, OtherThingName: "group", OtherFieldName: "members"},
+			},
+		},
+	}
+	group := types.ThingConfig{
+		Name:   "group",
+		Schema: "teams",
+		Fields: map[string]types.FieldConfig{
+			"members": {
+				Name:     "members",
+				Type:     types.RELATION,
+				Relation: types.ThingRelation{Type: types.MANY_TO_MANY, OtherThingName: "member", OtherFieldName: "groups"},
+			},
+		},
+	}
+
+	fromMember := member.GetJoinTable(member.Fields["groups"], group)
+	fromGroup := group.GetJoinTable(group.Fields["members"], member)
+
+	expected := `"teams"."group_members"`
+	if fromMember.GetTableName() != expected || fromGroup.GetTableName() != expected {
+		t.Fatalf("expected: %s got: %s and %s", expected, fromMember.GetTableName(), fromGroup.GetTableName())
+	}
+}
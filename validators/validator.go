@@ -0,0 +1,226 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"json2sql/types"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	ThingName  string           `json:"thingName"`
+	Violations []FieldViolation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, violation := range e.Violations {
+		messages = append(messages, fmt.Sprintf("field: %s %s", violation.Field, violation.Message))
+	}
+	return strings.Join(messages, "\n")
+}
+
+type Validator struct {
+	Partial    bool
+	violations []FieldViolation
+}
+
+func (v *Validator) Validate(thing types.ThingConfig, values map[string]any) error {
+	v.violations = []FieldViolation{}
+
+	for _, field := range thing.GetFields() {
+		value, ok := values[field.Name]
+		if !ok {
+			if !v.Partial && field.NotNull && field.Default == "" && field.Type != types.PRIMARY_KEY {
+				v.addViolation(field.Name, "is required")
+			}
+			continue
+		}
+
+		v.validateValue(field.Name, field, value)
+	}
+
+	if len(v.violations) > 0 {
+		return &ValidationError{ThingName: thing.Name, Violations: v.violations}
+	}
+	return nil
+}
+
+func (v *Validator) validateValue(path string, field types.FieldConfig, value any) {
+	if value == nil {
+		if field.NotNull {
+			v.addViolation(path, "is required")
+		}
+		return
+	}
+
+	switch field.Type {
+	case types.STRING:
+		s, ok := value.(string)
+		if !ok {
+			v.addViolation(path, "must be string")
+			return
+		}
+		v.validateString(path, field, s)
+	case types.NUMBER:
+		n, ok := types.ToFloat64(value)
+		if !ok {
+			v.addViolation(path, "must be number")
+			return
+		}
+		v.validateNumber(path, field, n)
+	case types.INTEGER, types.BIGINT:
+		n, ok := types.ToInt64(value)
+		if !ok {
+			v.addViolation(path, "must be integer")
+			return
+		}
+		if field.Type == types.INTEGER && (n < math.MinInt32 || n > math.MaxInt32) {
+			v.addViolation(path, fmt.Sprintf("must be between %d and %d", math.MinInt32, math.MaxInt32))
+			return
+		}
+		v.validateNumber(path, field, float64(n))
+	case types.DECIMAL:
+		n, ok := types.ToFloat64(value)
+		if !ok {
+			n, ok = parseFloat64(value)
+		}
+		if !ok {
+			v.addViolation(path, "must be decimal")
+			return
+		}
+		v.validateNumber(path, field, n)
+	case types.BOOLEAN:
+		if _, ok := value.(bool); !ok {
+			v.addViolation(path, "must be boolean")
+		}
+	case types.DATE:
+		if !isDate(value) {
+			v.addViolation(path, "must be date")
+		}
+	case types.TIMESTAMP:
+		if !isTimestamp(value) {
+			v.addViolation(path, "must be timestamp")
+		}
+	case types.TIME:
+		if !isTime(value) {
+			v.addViolation(path, "must be time")
+		}
+	case types.UUID:
+		s, ok := value.(string)
+		if !ok || !types.IsUUID(s) {
+			v.addViolation(path, "must be uuid")
+		}
+	case types.ENUM:
+		s, ok := value.(string)
+		if !ok || !slices.Contains(field.EnumValues, s) {
+			v.addViolation(path, fmt.Sprintf("must be one of %s", strings.Join(field.EnumValues, ", ")))
+		}
+	case types.ARRAY:
+		if !types.IsArrayItemType(field.ArrayOf) {
+			v.addViolation(path, fmt.Sprintf("cannot be array of %s", field.ArrayOf))
+			return
+		}
+		items, ok := types.ToSlice(value)
+		if !ok {
+			v.addViolation(path, "must be array")
+			return
+		}
+		itemField := field.GetArrayItemField()
+		for i, item := range items {
+			v.validateValue(fmt.Sprintf("%s[%d]", path, i), itemField, item)
+		}
+	case types.JSON:
+		if _, err := json.Marshal(value); err != nil {
+			v.addViolation(path, "must be json")
+		}
+	case types.THING, types.RELATION:
+		if _, ok := types.ToFloat64(value); !ok {
+			v.addViolation(path, "must be id")
+		}
+	}
+}
+
+func (v *Validator) validateString(path string, field types.FieldConfig, s string) {
+	if field.MaxLength > 0 && utf8.RuneCountInString(s) > field.MaxLength {
+		v.addViolation(path, fmt.Sprintf("must be at most %d characters", field.MaxLength))
+	}
+
+	if field.Pattern != "" {
+		pattern, err := regexp.Compile(field.Pattern)
+		if err != nil {
+			v.addViolation(path, fmt.Sprintf("has invalid pattern %s", field.Pattern))
+		} else if !pattern.MatchString(s) {
+			v.addViolation(path, fmt.Sprintf("must match pattern %s", field.Pattern))
+		}
+	}
+}
+
+func (v *Validator) validateNumber(path string, field types.FieldConfig, n float64) {
+	if field.Min != nil && n < *field.Min {
+		v.addViolation(path, fmt.Sprintf("must be at least %v", *field.Min))
+	}
+
+	if field.Max != nil && n > *field.Max {
+		v.addViolation(path, fmt.Sprintf("must be at most %v", *field.Max))
+	}
+}
+
+func (v *Validator) addViolation(path string, message string) {
+	v.violations = append(v.violations, FieldViolation{Field: path, Message: message})
+}
+
+func parseFloat64(value any) (float64, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+func isTimestamp(value any) bool {
+	switch t := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		_, err := time.Parse(time.RFC3339, t)
+		return err == nil
+	}
+	return false
+}
+
+func isTime(value any) bool {
+	switch t := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		return types.IsTime(t)
+	}
+	return false
+}
+
+func isDate(value any) bool {
+	switch d := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		if _, err := time.Parse(time.DateOnly, d); err == nil {
+			return true
+		}
+		_, err := time.Parse(time.RFC3339, d)
+		return err == nil
+	}
+	return false
+}
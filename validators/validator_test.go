@@ -0,0 +1,251 @@
+package validators_test
+
+import (
+	"encoding/json"
+	"errors"
+	"json2sql/types"
+	"json2sql/validators"
+	"testing"
+	"time"
+)
+
+var minNumber = 0.0
+var maxNumber = 10.0
+
+var validatedThing = types.ThingConfig{
+	Name: "validatedThing",
+	Fields: map[string]types.FieldConfig{
+		"primaryKey": {
+			Name: "primaryKey",
+			Type: types.PRIMARY_KEY,
+		},
+		"string": {
+			Name:      "string",
+			Type:      types.STRING,
+			NotNull:   true,
+			MaxLength: 5,
+			Pattern:   "^[a-z]+$",
+		},
+		"number": {
+			Name: "number",
+			Type: types.NUMBER,
+			Min:  &minNumber,
+			Max:  &maxNumber,
+		},
+		"boolean": {
+			Name: "boolean",
+			Type: types.BOOLEAN,
+		},
+		"date": {
+			Name: "date",
+			Type: types.DATE,
+		},
+	},
+}
+
+func TestValidValues(t *testing.T) {
+	validator := validators.Validator{}
+	err := validator.Validate(validatedThing, map[string]any{
+		"string":  "test",
+		"number":  5,
+		"boolean": true,
+		"date":    time.Now(),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalidValues(t *testing.T) {
+	validator := validators.Validator{}
+	err := validator.Validate(validatedThing, map[string]any{
+		"number":  11.5,
+		"boolean": "true",
+		"date":    "yesterday",
+	})
+
+	var validationError *validators.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected validation error got: %v", err)
+	}
+
+	expected := []validators.FieldViolation{
+		{Field: "boolean", Message: "must be boolean"},
+		{Field: "date", Message: "must be date"},
+		{Field: "number", Message: "must be at most 10"},
+		{Field: "string", Message: "is required"},
+	}
+
+	if len(validationError.Violations) != len(expected) {
+		t.Fatalf("expected: %v got: %v", expected, validationError.Violations)
+	}
+
+	for i, violation := range validationError.Violations {
+		if violation != expected[i] {
+			t.Fatalf("expected: %v got: %v", expected[i], violation)
+		}
+	}
+}
+
+func TestStringConstraints(t *testing.T) {
+	validator := validators.Validator{}
+	err := validator.Validate(validatedThing, map[string]any{
+		"string": "Test123",
+	})
+
+	expectedError := `field: string must be at most 5 characters
+field: string must match pattern ^[a-z]+$`
+
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestPartialValidation(t *testing.T) {
+	validator := validators.Validator{Partial: true}
+	err := validator.Validate(validatedThing, map[string]any{
+		"number": 1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtendedFieldTypes(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "typedThing",
+		Fields: map[string]types.FieldConfig{
+			"integer":   {Name: "integer", Type: types.INTEGER},
+			"decimal":   {Name: "decimal", Type: types.DECIMAL},
+			"timestamp": {Name: "timestamp", Type: types.TIMESTAMP},
+			"time":      {Name: "time", Type: types.TIME},
+			"uuid":      {Name: "uuid", Type: types.UUID},
+		},
+	}
+
+	validator := validators.Validator{}
+	err := validator.Validate(thing, map[string]any{
+		"integer":   7,
+		"decimal":   "12.50",
+		"timestamp": "2024-01-02T15:04:05Z",
+		"time":      "15:04",
+		"uuid":      "0b8f3a52-6a3e-4c1e-9d57-2a8c5c1f0e11",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validator.Validate(thing, map[string]any{
+		"integer":   7.5,
+		"decimal":   "twelve",
+		"timestamp": "2024-01-02",
+		"time":      "25:00",
+		"uuid":      "123",
+	})
+
+	expectedError := `field: decimal must be decimal
+field: integer must be integer
+field: time must be time
+field: timestamp must be timestamp
+field: uuid must be uuid`
+
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestIntegerRange(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "integerThing",
+		Fields: map[string]types.FieldConfig{
+			"integer": {Name: "integer", Type: types.INTEGER},
+			"bigint":  {Name: "bigint", Type: types.BIGINT},
+		},
+	}
+
+	validator := validators.Validator{}
+	err := validator.Validate(thing, map[string]any{
+		"integer": json.Number("2147483647"),
+		"bigint":  json.Number("9007199254740993"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validator.Validate(thing, map[string]any{
+		"integer": 2147483648,
+		"bigint":  1e300,
+	})
+
+	expectedError := `field: bigint must be integer
+field: integer must be between -2147483648 and 2147483647`
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "enumThing",
+		Fields: map[string]types.FieldConfig{
+			"status": {Name: "status", Type: types.ENUM, EnumValues: []string{"draft", "published"}},
+		},
+	}
+
+	validator := validators.Validator{}
+	err := validator.Validate(thing, map[string]any{"status": "draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validator.Validate(thing, map[string]any{"status": "archived"})
+
+	expectedError := "field: status must be one of draft, published"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}
+
+func TestArrayValues(t *testing.T) {
+	thing := types.ThingConfig{
+		Name: "arrayThing",
+		Fields: map[string]types.FieldConfig{
+			"tags": {Name: "tags", Type: types.ARRAY, ArrayOf: types.STRING},
+		},
+	}
+
+	validator := validators.Validator{}
+	err := validator.Validate(thing, map[string]any{"tags": []string{"red"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validator.Validate(thing, map[string]any{"tags": []any{"red", 1}})
+
+	expectedError := "field: tags[1] must be string"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+
+	thing.Fields["tags"] = types.FieldConfig{Name: "tags", Type: types.ARRAY, ArrayOf: types.DECIMAL}
+	err = validator.Validate(thing, map[string]any{"tags": []any{"1.50"}})
+
+	expectedError = "field: tags cannot be array of DECIMAL"
+	if err == nil {
+		t.Fatal("error expected")
+	} else if err.Error() != expectedError {
+		t.Fatalf("expected error: %s got: %s", expectedError, err)
+	}
+}